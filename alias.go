@@ -0,0 +1,72 @@
+package funcmaps
+
+import (
+	"reflect"
+	"sort"
+)
+
+// aliases maps the alternative names of functions of this package to their
+// canonical names.
+var aliases = map[string]string{
+	"lower":   "toLower",
+	"upper":   "toUpper",
+	"ternary": "yesno",
+
+	"intersect": "intersection",
+}
+
+// Alias adds the functions named by the keys of m as aliases of the
+// functions named by the values, such as {"lowercase": "toLower"} for names
+// familiar from other template languages. Aliases of missing functions are
+// not added.
+func Alias(m map[string]string) MapOption {
+	return func(o *mapOptions) {
+		o.edits = append(o.edits, func(fm FuncMap) { addAliases(fm, m) })
+	}
+}
+
+// addAliases adds the aliases of m to the functions of fm, and returns fm.
+func addAliases(fm FuncMap, m map[string]string) FuncMap {
+	for alias, name := range m {
+		if fn, ok := fm[name]; ok {
+			fm[alias] = fn
+		}
+	}
+	return fm
+}
+
+// dropAliases removes the aliases of the function named name from fm,
+// unless they were replaced by other functions.
+func dropAliases(fm FuncMap, name string) {
+	fn := reflect.ValueOf(fm[name])
+	if fn.Kind() != reflect.Func {
+		return
+	}
+	for alias, c := range aliases {
+		if a := reflect.ValueOf(fm[alias]); c == name && a.Kind() == reflect.Func && a.Pointer() == fn.Pointer() {
+			delete(fm, alias)
+		}
+	}
+}
+
+// Canonical returns the name of the function of this package that name is
+// an alias of, or name if it is not an alias.
+func Canonical(name string) string {
+	if c, ok := aliases[name]; ok {
+		return c
+	}
+	return name
+}
+
+// AliasesOf returns the aliases of the function of this package named
+// name, sorted.
+func AliasesOf(name string) []string {
+	var rs []string
+	for alias, c := range aliases {
+		if c == name {
+			rs = append(rs, alias)
+		}
+	}
+	sort.Strings(rs)
+	return rs
+}
@@ -0,0 +1,23 @@
+package funcmaps
+
+import "testing"
+
+func TestAliasesFollowEdits(t *testing.T) {
+	if fm := Default(); fm["lower"] == nil {
+		t.Fatal("Default has no lower alias")
+	}
+	if fm := Default(Without("toLower")); fm["lower"] != nil {
+		t.Error("Without(toLower) kept lower")
+	}
+	fm := Default(Rename("toLower", "lc"))
+	if fm["lower"] != nil || fm["toLower"] != nil || fm["lc"] == nil {
+		t.Errorf("Rename(toLower, lc): lower %v, toLower %v, lc %v", fm["lower"] != nil, fm["toLower"] != nil, fm["lc"] != nil)
+	}
+	fm = Default(Alias(map[string]string{"lower": "toUpper"}), Without("toLower"))
+	if fm["lower"] == nil {
+		t.Error("Without(toLower) removed lower, an alias of toUpper")
+	}
+	if fm := Default(Without("lower")); fm["lower"] != nil || fm["toLower"] == nil {
+		t.Error("Without(lower) did not remove only lower")
+	}
+}
@@ -0,0 +1,59 @@
+package funcmaps
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Assertions returns functions checking the data given to templates,
+// failing the execution when enabled, as in development, and doing nothing
+// otherwise:
+//
+//	{{assert (gt (len .Items) 0) "items must not be empty"}}
+//	{{assertType "[]string" .Tags}}
+//
+// Both output nothing. assertType accepts a type as printed by %T, such as
+// "map[string]interface {}" ("any" and "interface{}" are accepted too), or
+// a kind such as "slice", "map" or "struct".
+func Assertions(enabled bool) FuncMap {
+	if !enabled {
+		return FuncMap{
+			"assert":     func(interface{}, string) string { return "" },
+			"assertType": func(string, interface{}) string { return "" },
+		}
+	}
+	return FuncMap{
+		"assert":     Assert,
+		"assertType": AssertType,
+	}
+}
+
+// Assert returns an error with msg if cond is not true in the sense of
+// IsTrue.
+func Assert(cond interface{}, msg string) (string, error) {
+	if !IsTrue(cond) {
+		return "", fmt.Errorf("assertion failed: %s", msg)
+	}
+	return "", nil
+}
+
+// anyTypeRe matches the spellings of the empty interface in type names.
+var anyTypeRe = regexp.MustCompile(`\bany\b|interface\{\}`)
+
+// AssertType returns an error if v is not of the type or kind typ.
+func AssertType(typ string, v interface{}) (string, error) {
+	want := anyTypeRe.ReplaceAllString(strings.TrimSpace(typ), "interface {}")
+	t := reflect.TypeOf(v)
+	if t == nil {
+		if want == "nil" {
+			return "", nil
+		}
+		return "", fmt.Errorf("assertion failed: expected %s, got nil", typ)
+	}
+	if t.String() == want || t.Kind().String() == want {
+		return "", nil
+	}
+	return "", fmt.Errorf("assertion failed: expected %s, got %s", typ, t)
+}
@@ -0,0 +1,194 @@
+package funcmaps
+
+import (
+	"html/template"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Risk is the risk tier of a template function, from RiskPure to
+// RiskUnescapedOutput. Tiers are ordered, so that a security review can
+// check that a FuncMap has no function above a chosen tier.
+type Risk int
+
+const (
+	RiskPure            Risk = iota // result depends only on the arguments
+	RiskReadsEnv                    // reads environment variables, the clock, random numbers or host details
+	RiskReadsFS                     // reads files or runs programs
+	RiskNetwork                     // makes network requests
+	RiskUnescapedOutput             // returns content html/template does not escape
+)
+
+var riskNames = [...]string{"pure", "reads-env", "reads-fs", "network", "unescaped-output"}
+
+func (r Risk) String() string {
+	if r < 0 || int(r) >= len(riskNames) {
+		return "unknown"
+	}
+	return riskNames[r]
+}
+
+// funcRisks are the risks of the functions of this package that are not
+// pure, and of those returning html/template content that they escaped.
+var funcRisks = map[string]Risk{
+	// reads the environment
+	"env": RiskReadsEnv, "now": RiskReadsEnv, "NOW": RiskReadsEnv, "uuid": RiskReadsEnv,
+	"conf": RiskReadsEnv, "hasConf": RiskReadsEnv,
+	"buildInfo": RiskReadsEnv, "goVersion": RiskReadsEnv, "hostname": RiskReadsEnv,
+	"pid": RiskReadsEnv, "uptime": RiskReadsEnv, "numCPU": RiskReadsEnv,
+	"uuidv4": RiskReadsEnv, "uuidv7": RiskReadsEnv, "ulid": RiskReadsEnv,
+	"nanoid": RiskReadsEnv, "shortid": RiskReadsEnv, "listTimezones": RiskReadsEnv,
+	"naturalTime": RiskReadsEnv, "naturalDay": RiskReadsEnv,
+	"date": RiskReadsEnv, "date_locale": RiskReadsEnv, // the clock, for values other than times
+
+	// reads files or runs git
+	"assetHash": RiskReadsFS, "assetURL": RiskReadsFS, "assetV": RiskReadsFS, "imageDims": RiskReadsFS,
+	"dataURI": RiskReadsFS, "picture": RiskReadsFS, "icon": RiskReadsFS, "seqLines": RiskReadsFS,
+	"gitSHA": RiskReadsFS, "gitShortSHA": RiskReadsFS, "gitTag": RiskReadsFS,
+	"gitBranch": RiskReadsFS, "gitCommitTime": RiskReadsFS, "gitDescribe": RiskReadsFS,
+
+	// return their input as trusted content
+	"unsafeCSS": RiskUnescapedOutput, "unsafeHTML": RiskUnescapedOutput,
+	"unsafeHTMLAttr": RiskUnescapedOutput, "unsafeJS": RiskUnescapedOutput,
+	"unsafeURL": RiskUnescapedOutput, "inlineCSS": RiskUnescapedOutput, "safe": RiskUnescapedOutput,
+
+	// return content they built or escaped themselves
+	"sanitize": RiskPure, "highlight": RiskPure, "highlightMatch": RiskPure,
+	"diffHTML": RiskPure, "emailSafeImg": RiskPure, "preheader": RiskPure,
+	"barcode": RiskPure, "qrcode": RiskPure, "initialsAvatar": RiskPure,
+	"telLink": RiskPure, "mailtoLink": RiskPure, "linebreaksbr": RiskPure,
+	"breadcrumbJSONLD": RiskPure, "sitemapURL": RiskPure,
+	"cssVar": RiskPure, "themeValue": RiskPure, "themeStyles": RiskPure,
+	"partial": RiskPure, "cachedPartial": RiskPure, "content": RiskPure, "yield": RiskPure,
+}
+
+// recordedRisks maps the code pointers of the functions of this package
+// that are not pure to their risks, so that Audit recognizes them under
+// other names, such as given by Rename or Alias.
+var (
+	recordedRisksMu sync.RWMutex
+	recordedRisks   = map[uintptr]Risk{}
+)
+
+// recordRisks records the risks of the functions of fm, a map of this
+// package as built by its constructor, and returns fm.
+func recordRisks(fm FuncMap) FuncMap {
+	recordedRisksMu.Lock()
+	defer recordedRisksMu.Unlock()
+	for name, fn := range fm {
+		r := funcRisks[name]
+		if v := reflect.ValueOf(fn); r > RiskPure && v.Kind() == reflect.Func && !v.IsNil() && r > recordedRisks[v.Pointer()] {
+			recordedRisks[v.Pointer()] = r
+		}
+	}
+	return fm
+}
+
+// recordedRisk returns the risk recorded by recordRisks for fn.
+func recordedRisk(fn interface{}) (Risk, bool) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return RiskPure, false
+	}
+	recordedRisksMu.RLock()
+	defer recordedRisksMu.RUnlock()
+	r, ok := recordedRisks[v.Pointer()]
+	return r, ok
+}
+
+// contentTypes are the html/template types of content that is not escaped.
+var contentTypes = map[reflect.Type]bool{
+	reflect.TypeOf(template.CSS("")):      true,
+	reflect.TypeOf(template.HTML("")):     true,
+	reflect.TypeOf(template.HTMLAttr("")): true,
+	reflect.TypeOf(template.JS("")):       true,
+	reflect.TypeOf(template.JSStr("")):    true,
+	reflect.TypeOf(template.Srcset("")):   true,
+	reflect.TypeOf(template.URL("")):      true,
+}
+
+// AuditEntry is the risk of a function of an audited FuncMap.
+type AuditEntry struct {
+	Name      string
+	Signature string
+	Risk      Risk
+}
+
+// AuditReport is the result of Audit, sorted by name.
+type AuditReport []AuditEntry
+
+// Audit classifies the functions of fm by risk, for security reviews:
+//
+//	if risky := funcmaps.Audit(fm).Above(funcmaps.RiskReadsEnv); len(risky) > 0 {
+//		t.Errorf("web templates can use %v", risky)
+//	}
+//
+// Functions are classified first by name, by the maps in known, the last
+// one first, which classify the functions of other packages. Then they
+// are classified as the functions of this package with that name, or as
+// the function they are, whatever its name, if it is one of the functions
+// of this package that are not pure, taking the higher risk. Other
+// functions are RiskUnescapedOutput if they return html/template content
+// types such as template.HTML, or RiskPure.
+//
+// Functions are recognized by their code, so functions of this package
+// both renamed and wrapped, such as by Guard or Limit, or wrapped in a
+// function of another package, are only recognized by their names and
+// should be classified with known.
+func Audit(fm FuncMap, known ...map[string]Risk) AuditReport {
+	report := make(AuditReport, 0, len(fm))
+	for name, fn := range fm {
+		report = append(report, AuditEntry{Name: name, Signature: signature(fn), Risk: funcRisk(name, fn, known)})
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Name < report[j].Name })
+	return report
+}
+
+func funcRisk(name string, fn interface{}, known []map[string]Risk) Risk {
+	for i := len(known) - 1; i >= 0; i-- {
+		if r, ok := known[i][name]; ok {
+			return r
+		}
+	}
+	r, byName := funcRisks[name]
+	if rr, ok := recordedRisk(fn); ok && rr > r {
+		return rr
+	}
+	if byName {
+		return r
+	}
+	if typ := reflect.TypeOf(fn); typ != nil && typ.Kind() == reflect.Func {
+		for i := 0; i < typ.NumOut(); i++ {
+			if contentTypes[typ.Out(i)] {
+				return RiskUnescapedOutput
+			}
+		}
+	}
+	return RiskPure
+}
+
+// Above returns the entries of r with a risk above max.
+func (r AuditReport) Above(max Risk) AuditReport {
+	var rs AuditReport
+	for _, e := range r {
+		if e.Risk > max {
+			rs = append(rs, e)
+		}
+	}
+	return rs
+}
+
+// Names returns the names of the functions in r.
+func (r AuditReport) Names() []string {
+	names := make([]string, len(r))
+	for i, e := range r {
+		names[i] = e.Name
+	}
+	return names
+}
+
+// String returns the entry as "name (risk)".
+func (e AuditEntry) String() string {
+	return e.Name + " (" + e.Risk.String() + ")"
+}
@@ -0,0 +1,41 @@
+package funcmaps
+
+import "testing"
+
+func TestAuditClock(t *testing.T) {
+	risks := map[string]Risk{}
+	for _, e := range Audit(Combined(Default(), Django())) {
+		risks[e.Name] = e.Risk
+	}
+	for _, name := range []string{"now", "date", "date_locale"} {
+		if risks[name] != RiskReadsEnv {
+			t.Errorf("%s is %v, want %v", name, risks[name], RiskReadsEnv)
+		}
+	}
+}
+
+func TestAudit(t *testing.T) {
+	custom := func(string) string { return "" }
+	fm := Combined(Default(Rename("env", "getenv")), Trusted(), FuncMap{"custom": custom, "trustedAd": HTML})
+	risks := map[string]Risk{}
+	for _, e := range Audit(fm, map[string]Risk{"custom": RiskNetwork}) {
+		risks[e.Name] = e.Risk
+	}
+	for name, want := range map[string]Risk{
+		"toUpper":    RiskPure,
+		"getenv":     RiskReadsEnv,
+		"unsafeHTML": RiskUnescapedOutput,
+		"trustedAd":  RiskUnescapedOutput,
+		"custom":     RiskNetwork,
+	} {
+		if risks[name] != want {
+			t.Errorf("%s is %v, want %v", name, risks[name], want)
+		}
+	}
+	above := Audit(fm).Above(RiskReadsEnv).Names()
+	for _, name := range above {
+		if risks[name] <= RiskReadsEnv {
+			t.Errorf("Above(RiskReadsEnv) includes %s (%v)", name, risks[name])
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package funcmaps
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Avatars returns functions building avatar image URLs.
+func Avatars() FuncMap {
+	return FuncMap{
+		"gravatar":       Gravatar,
+		"identiconURL":   IdenticonURL,
+		"initialsAvatar": InitialsAvatar,
+	}
+}
+
+// Gravatar returns the Gravatar image URL for email at size pixels,
+// falling back to a generic silhouette when the address has no Gravatar.
+func Gravatar(email string, size int) string {
+	return gravatarURL(email, size, url.Values{"d": {"mp"}})
+}
+
+// IdenticonURL returns the URL of a size pixel geometric pattern generated
+// from email, ignoring any Gravatar uploaded for the address.
+func IdenticonURL(email string, size int) string {
+	return gravatarURL(email, size, url.Values{"d": {"identicon"}, "f": {"y"}})
+}
+
+func gravatarURL(email string, size int, q url.Values) string {
+	sum := md5.Sum([]byte(strings.ToLower(strings.TrimSpace(email))))
+	if size > 0 {
+		q.Set("s", strconv.Itoa(size))
+	}
+	return "https://www.gravatar.com/avatar/" + hex.EncodeToString(sum[:]) + "?" + q.Encode()
+}
+
+// InitialsAvatar returns an SVG data URI showing the initials of name on a
+// background color derived from name.
+func InitialsAvatar(name string) template.URL {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	bg := hslToRGB(float64(h.Sum32()%360)/360, 0.5, 0.45).Hex()
+	fg, _ := ContrastColor(bg)
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64">`+
+		`<rect width="64" height="64" fill="%s"/>`+
+		`<text x="50%%" y="50%%" dy=".35em" fill="%s" font-family="sans-serif" font-size="26" text-anchor="middle">%s</text>`+
+		`</svg>`, bg, fg, template.HTMLEscapeString(Initials(name, 2)))
+	return template.URL("data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(svg)))
+}
@@ -0,0 +1,104 @@
+package funcmaps
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Filter is a filter of filter-based template engines such as pongo2,
+// called with the filtered value and the filter parameter, which is nil
+// when none was given: {{ value|name:param }}.
+type Filter func(in, param interface{}) (interface{}, error)
+
+// Filters returns the functions of fm usable as filters: those taking the
+// subject as their only argument, and those taking a parameter followed by
+// the subject, as the functions of this package do. Register them with
+// pongo2 through a small shim:
+//
+//	for name, f := range funcmaps.Filters(fm) {
+//		f := f
+//		pongo2.RegisterFilter(name, func(in, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+//			out, err := f(in.Interface(), param.Interface())
+//			if err != nil {
+//				return nil, &pongo2.Error{OrigError: err}
+//			}
+//			return pongo2.AsValue(out), nil
+//		})
+//	}
+func Filters(fm FuncMap) map[string]Filter {
+	rs := map[string]Filter{}
+	for name, f := range fm {
+		fv := reflect.ValueOf(f)
+		if fv.Kind() != reflect.Func || fv.IsNil() {
+			continue
+		}
+		typ := fv.Type()
+		if typ.IsVariadic() || typ.NumIn() < 1 || typ.NumIn() > 2 || !goodFunc(typ) {
+			continue
+		}
+		rs[name] = filterFunc(name, fv)
+	}
+	return rs
+}
+
+func filterFunc(name string, fv reflect.Value) Filter {
+	typ := fv.Type()
+	return func(in, param interface{}) (interface{}, error) {
+		vals := []interface{}{in}
+		if typ.NumIn() == 2 {
+			vals = []interface{}{param, in}
+		}
+		args := make([]reflect.Value, len(vals))
+		for i, v := range vals {
+			arg, err := convertArg(v, typ.In(i))
+			if err != nil {
+				return nil, fmt.Errorf("%s: argument %d: %w", name, i+1, err)
+			}
+			args[i] = arg
+		}
+		out := fv.Call(args)
+		if err := callError(out); err != nil {
+			return nil, err
+		}
+		return out[0].Interface(), nil
+	}
+}
+
+// convertArg converts v to a value of type typ: values are used as they
+// are when assignable, and converted when convertible, as between numeric
+// types. nil is the zero value of typ.
+func convertArg(v interface{}, typ reflect.Type) (reflect.Value, error) {
+	if v == nil {
+		return reflect.Zero(typ), nil
+	}
+	rv := reflect.ValueOf(v)
+	switch {
+	case typ == reflectValueType:
+		return reflect.ValueOf(rv), nil
+	case rv.Type().AssignableTo(typ):
+		return rv, nil
+	case rv.Type().ConvertibleTo(typ) && rv.Kind() != reflect.String && typ.Kind() != reflect.String:
+		return rv.Convert(typ), nil
+	case typ.Kind() == reflect.String:
+		s, err := toText(v)
+		return reflect.ValueOf(s).Convert(typ), err
+	}
+	return reflect.Value{}, fmt.Errorf("cannot use %T as %s", v, typ)
+}
+
+// Helpers returns the functions of fm as helpers for engines calling plain
+// Go functions by name, such as plush:
+//
+//	plush.Helpers.AddMany(funcmaps.Helpers(fm))
+//
+// Helpers are called with their arguments in the order written, so the
+// subject comes last: <%= trim("-", name) %>.
+func Helpers(fm FuncMap) map[string]interface{} {
+	rs := make(map[string]interface{}, len(fm))
+	for name, f := range fm {
+		if reflect.ValueOf(f).Kind() == reflect.Func {
+			rs[name] = f
+		}
+	}
+	return rs
+}
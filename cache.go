@@ -0,0 +1,259 @@
+package funcmaps
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CacheStore stores cached values. Implementations must be safe for
+// concurrent use; they may be backed by memcached, Redis or the like, as
+// long as the values they return are usable by the templates.
+type CacheStore interface {
+	// Get returns the value stored for key, if present and not expired.
+	Get(key string) (interface{}, bool)
+	// Set stores value for key. A zero ttl means the value does not expire.
+	Set(key string, value interface{}, ttl time.Duration)
+}
+
+// Cache memoizes template fragments and function results across
+// executions, for pages that are rendered often but change rarely.
+type Cache struct {
+	store CacheStore
+
+	mu      sync.Mutex
+	pending map[string]*cacheCall // computations in progress, by key
+}
+
+// cacheCall is a computation of a value, waited on by concurrent callers.
+type cacheCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// NewCache returns a Cache storing values in store, or in a new MemoryStore
+// if store is nil.
+func NewCache(store CacheStore) *Cache {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	return &Cache{store: store, pending: map[string]*cacheCall{}}
+}
+
+// Funcs returns the cached function, bound to c.
+func (c *Cache) Funcs() FuncMap {
+	return FuncMap{
+		"cached": c.Cached,
+	}
+}
+
+// Cached returns the value cached for key, or else v, caching it for ttl.
+//
+// When v is a function taking no arguments, such as a func-typed field, it
+// is called only when the key is not cached, so expensive work is skipped:
+//
+//	{{ cached "top-posts" "5m" .LoadTopPosts }}
+//
+// The ttl is a time.Duration, a duration string such as "90s", or a number
+// of seconds; zero caches the value until it is evicted. Errors are not
+// cached, and concurrent misses for the same key compute the value once.
+func (c *Cache) Cached(key string, ttl interface{}, v interface{}) (interface{}, error) {
+	d, err := toDuration(ttl)
+	if err != nil {
+		return nil, err
+	}
+	if d < 0 {
+		return nil, fmt.Errorf("cached: negative ttl %v", d)
+	}
+	return c.get(key, d, func() (interface{}, error) { return callLazy(v) })
+}
+
+func (c *Cache) get(key string, ttl time.Duration, compute func() (interface{}, error)) (interface{}, error) {
+	if v, ok := c.store.Get(key); ok {
+		return v, nil
+	}
+	c.mu.Lock()
+	if call, ok := c.pending[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.value, call.err
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	c.pending[key] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, key)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+	call.value, call.err = computeSafely(compute)
+	if call.err == nil {
+		c.store.Set(key, call.value, ttl)
+	}
+	return call.value, call.err
+}
+
+// computeSafely calls compute, returning a panic as an error, so that the
+// callers waiting for the value get it too.
+func computeSafely(compute func() (interface{}, error)) (v interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			v, err = nil, fmt.Errorf("cached: %w", panicError(p))
+		}
+	}()
+	return compute()
+}
+
+// cachedPartialFunc returns the cachedPartial function of Templates using
+// cache, rendering partials with partial.
+func cachedPartialFunc(cache *Cache, partial func(string, ...interface{}) (template.HTML, error)) func(string, interface{}, string, ...interface{}) (template.HTML, error) {
+	return func(key string, ttl interface{}, name string, data ...interface{}) (template.HTML, error) {
+		if cache == nil {
+			return "", errNoCache
+		}
+		d, err := toDuration(ttl)
+		if err != nil {
+			return "", err
+		}
+		if d < 0 {
+			return "", fmt.Errorf("cachedPartial: negative ttl %v", d)
+		}
+		v, err := cache.get(key, d, func() (interface{}, error) { return partial(name, data...) })
+		if err != nil {
+			return "", err
+		}
+		switch v := v.(type) {
+		case template.HTML:
+			return v, nil
+		case string: // from stores that serialize values
+			return template.HTML(v), nil
+		}
+		return "", fmt.Errorf("cachedPartial: unexpected %T cached for %q", v, key)
+	}
+}
+
+var errNoCache = errors.New("cachedPartial requires a Templates created with WithCache")
+
+// WithCache makes the cachedPartial function of Templates cache partials in
+// cache. {{cachedPartial "sidebar" "5m" "sidebar" .}} renders the sidebar
+// partial at most every five minutes; the key must identify the data.
+func WithCache(cache *Cache) Option {
+	return func(t *Templates) {
+		t.cache = cache
+	}
+}
+
+// callLazy calls v if it is a function taking no arguments, returning its
+// result and error, if any. Other values are returned as they are.
+func callLazy(v interface{}) (interface{}, error) {
+	fv := reflect.ValueOf(v)
+	if fv.Kind() != reflect.Func || fv.IsNil() || fv.Type().NumIn() != 0 {
+		return v, nil
+	}
+	typ := fv.Type()
+	switch {
+	case typ.NumOut() == 1 && typ.Out(0) != errorType:
+	case typ.NumOut() == 2 && typ.Out(1) == errorType:
+	default:
+		return nil, fmt.Errorf("cannot call %s: want one result, and an optional error", typ)
+	}
+	out := fv.Call(nil)
+	if err := callError(out); err != nil {
+		return nil, err
+	}
+	return out[0].Interface(), nil
+}
+
+// toDuration converts a time.Duration, a duration string or a number of
+// seconds to a time.Duration.
+func toDuration(v interface{}) (time.Duration, error) {
+	switch d := v.(type) {
+	case time.Duration:
+		return d, nil
+	case string:
+		if secs, err := strconv.ParseFloat(d, 64); err == nil {
+			return time.Duration(secs * float64(time.Second)), nil
+		}
+		return time.ParseDuration(d)
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Duration(rv.Int()) * time.Second, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return time.Duration(rv.Uint()) * time.Second, nil
+	case reflect.Float32, reflect.Float64:
+		return time.Duration(rv.Float() * float64(time.Second)), nil
+	}
+	return 0, fmt.Errorf("invalid duration %v of type %T", v, v)
+}
+
+// MemoryStore is an in-memory CacheStore.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	sets    int // calls to Set since the last sweep of expired entries
+}
+
+type memoryEntry struct {
+	value   interface{}
+	expires time.Time // zero if the entry does not expire
+}
+
+// memorySweepEvery is the number of calls to Set between sweeps of the
+// expired entries of a MemoryStore.
+const memorySweepEvery = 1024
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]memoryEntry{}}
+}
+
+// Get returns the value stored for key, if present and not expired.
+func (s *MemoryStore) Get(key string) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !e.expires.IsZero() && !time.Now().Before(e.expires) {
+		delete(s.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set stores value for key for ttl, or without expiry if ttl is zero.
+func (s *MemoryStore) Set(key string, value interface{}, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	e := memoryEntry{value: value}
+	if ttl > 0 {
+		e.expires = now.Add(ttl)
+	}
+	s.entries[key] = e
+	if s.sets++; s.sets >= memorySweepEvery {
+		s.sets = 0
+		for k, e := range s.entries {
+			if !e.expires.IsZero() && !now.Before(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+	}
+}
+
+// Delete removes the value stored for key.
+func (s *MemoryStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
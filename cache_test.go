@@ -0,0 +1,84 @@
+package funcmaps
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCached(t *testing.T) {
+	c := NewCache(nil)
+	calls := 0
+	load := func() int { calls++; return calls }
+	for i := 0; i < 2; i++ {
+		v, err := c.Cached("k", "1m", load)
+		if err != nil || v != 1 {
+			t.Errorf("Cached: got %v, %v, want 1", v, err)
+		}
+	}
+	if _, err := c.Cached("k2", "-1s", load); err == nil {
+		t.Error("Cached with a negative ttl: got nil error")
+	}
+	if _, err := c.Cached("k3", "soon", load); err == nil {
+		t.Error("Cached with an invalid ttl: got nil error")
+	}
+}
+
+func TestCachedPanic(t *testing.T) {
+	c := NewCache(nil)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = c.Cached("k", 0, func() int { panic("boom") })
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	for i, err := range errs {
+		if err == nil || !strings.Contains(err.Error(), "boom") {
+			t.Errorf("caller %d: got error %v, want boom", i, err)
+		}
+	}
+}
+
+func TestCachedPartial(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.html":  {Data: []byte(`{{cachedPartial "side" "1m" "side" .}}`)},
+		"bad.html":   {Data: []byte(`{{cachedPartial "side" "-1m" "side" .}}`)},
+		"_side.html": {Data: []byte(`<b>{{.}}</b>`)},
+	}
+	ts := New(Default(), WithCache(NewCache(nil)))
+	if err := ts.ParseFS(fsys, "page.html", "bad.html"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.ParsePartials(fsys); err != nil {
+		t.Fatal(err)
+	}
+	for _, data := range []string{"a", "b"} {
+		var buf bytes.Buffer
+		if err := ts.ExecuteTemplate(&buf, "page.html", data); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := buf.String(), "<b>a</b>"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+	var buf bytes.Buffer
+	if err := ts.ExecuteTemplate(&buf, "bad.html", "a"); err == nil || !strings.Contains(err.Error(), "negative ttl") {
+		t.Errorf("negative ttl: got error %v", err)
+	}
+	ts = New(Default())
+	if err := ts.ParseFS(fsys, "page.html"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.ExecuteTemplate(&buf, "page.html", "a"); err == nil {
+		t.Error("cachedPartial without a cache: got nil error")
+	}
+}
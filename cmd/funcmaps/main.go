@@ -0,0 +1,162 @@
+// Command funcmaps inspects the functions of the funcmaps package.
+//
+// Usage:
+//
+//	funcmaps snapshot              print the names and signatures of All
+//	funcmaps compare old [new]     compare two snapshots, or old with All
+//	funcmaps preview file...       render an HTML template with sample data
+//	funcmaps usage file...         list the functions templates call
+//	funcmaps lint dir              check the templates in dir
+//
+// compare prints one line per added (+), removed (-) or re-typed (~)
+// function, and exits with status 1 when there are any, so that a build
+// can fail when an upgrade changes functions its templates rely on.
+//
+// preview parses the files as html/template templates with the functions
+// of All and SampleData, and executes the first one, which can use
+// {{sample "users" 3}} and the like in place of real data.
+//
+// usage prints each function called by the files, which are patterns
+// relative to the current directory, and the templates calling it. The
+// unknown functions are marked with !, and make usage exit with status 1.
+//
+// lint prints the issues found by funcmaps.Lint in every file of dir, and
+// exits with status 1 when there are any.
+//
+// usage and lint know the functions of All, those Templates adds, such as
+// partial, counter and uniqueID, and those of the groups created without
+// arguments, such as Structs, Convert and Humanize.
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aerth/funcmaps"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "snapshot":
+		if len(args) != 0 {
+			usage()
+		}
+		if _, err := funcmaps.Snapshot().WriteTo(os.Stdout); err != nil {
+			fatal(err)
+		}
+	case "compare":
+		if len(args) < 1 || len(args) > 2 {
+			usage()
+		}
+		old, err := readSnapshot(args[0])
+		if err != nil {
+			fatal(err)
+		}
+		cur := funcmaps.Snapshot()
+		if len(args) == 2 {
+			if cur, err = readSnapshot(args[1]); err != nil {
+				fatal(err)
+			}
+		}
+		changes := funcmaps.CompareSnapshots(old, cur)
+		for _, c := range changes {
+			fmt.Println(c)
+		}
+		if len(changes) > 0 {
+			os.Exit(1)
+		}
+	case "preview":
+		if len(args) == 0 {
+			usage()
+		}
+		fm := funcmaps.Combined(funcmaps.All(), funcmaps.SampleData())
+		t, err := template.New("").Funcs(template.FuncMap(fm)).ParseFiles(args...)
+		if err != nil {
+			fatal(err)
+		}
+		if err := t.ExecuteTemplate(os.Stdout, filepath.Base(args[0]), nil); err != nil {
+			fatal(err)
+		}
+	case "usage":
+		if len(args) == 0 {
+			usage()
+		}
+		u, err := funcmaps.ParseUsage(os.DirFS("."), args...)
+		if err != nil {
+			fatal(err)
+		}
+		fm := known()
+		missing := false
+		for _, name := range u.Names() {
+			mark := " "
+			if _, ok := fm[name]; !ok {
+				mark, missing = "!", true
+			}
+			fmt.Printf("%s %s\t%s\n", mark, name, strings.Join(u[name], " "))
+		}
+		if missing {
+			os.Exit(1)
+		}
+	case "lint":
+		if len(args) != 1 {
+			usage()
+		}
+		issues, err := funcmaps.Lint(os.DirFS(args[0]), known())
+		if err != nil {
+			fatal(err)
+		}
+		for _, issue := range issues {
+			issue.Template = filepath.Join(args[0], issue.Template)
+			fmt.Println(issue)
+		}
+		if len(issues) > 0 {
+			os.Exit(1)
+		}
+	default:
+		usage()
+	}
+}
+
+// known returns the functions checked templates can call. The functions of
+// All win over those of the groups with the same name.
+func known() funcmaps.FuncMap {
+	return funcmaps.Combined(
+		funcmaps.Avatars(), funcmaps.Codegen(), funcmaps.Codes(), funcmaps.Collections(),
+		funcmaps.Colors(), funcmaps.Convert(), funcmaps.Diff(), funcmaps.Django(),
+		funcmaps.Email(), funcmaps.Encoding(), funcmaps.Escapes(), funcmaps.Fuzzy(),
+		funcmaps.Handlebars(), funcmaps.Humanize(), funcmaps.IDs(), funcmaps.K8s(),
+		funcmaps.Markup(), funcmaps.MIME(), funcmaps.Money(), funcmaps.Names(),
+		funcmaps.Phone(), funcmaps.Privacy(), funcmaps.Runtime(), funcmaps.Sanitizers(),
+		funcmaps.SEO(), funcmaps.Semver(), funcmaps.Shell(), funcmaps.SQL(),
+		funcmaps.Statistics(), funcmaps.Structs(), funcmaps.Tables(), funcmaps.Text(),
+		funcmaps.Timezones(), funcmaps.Types(), funcmaps.Unicode(), funcmaps.URLs(),
+		funcmaps.Validate(),
+		funcmaps.TemplateFuncs(funcmaps.Counters, funcmaps.ElementIDs),
+		funcmaps.All(),
+	)
+}
+
+func readSnapshot(name string) (funcmaps.FuncSnapshot, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return funcmaps.ReadSnapshot(f)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: funcmaps snapshot | compare old.json [new.json] | preview file... | usage file... | lint dir")
+	os.Exit(2)
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "funcmaps:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,171 @@
+package funcmaps
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// goCommentWidth is the width goComment wraps text to.
+const goCommentWidth = 80
+
+// Codegen returns functions for templates generating Go source code, such
+// as those run by go:generate.
+func Codegen() FuncMap {
+	return FuncMap{
+		"export":       Export,
+		"unexport":     Unexport,
+		"receiverName": ReceiverName,
+		"goType":       GoType,
+		"zeroValue":    ZeroValue,
+		"goComment":    GoComment,
+		"importAlias":  ImportAlias,
+	}
+}
+
+// Export returns s with its first letter upper cased, making it an exported
+// Go identifier.
+func Export(s string) string {
+	if s == "" {
+		return ""
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[n:]
+}
+
+// Unexport returns s with its first letter lower cased, making it an
+// unexported Go identifier.
+func Unexport(s string) string {
+	if s == "" {
+		return ""
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[n:]
+}
+
+// ReceiverName returns the conventional receiver name for a method of the
+// type typ, its first letter lower cased: "*pkg.UserStore" gives "u".
+func ReceiverName(typ string) string {
+	typ = strings.TrimLeft(typ, "*[]")
+	if i := strings.LastIndex(typ, "."); i >= 0 {
+		typ = typ[i+1:]
+	}
+	for _, r := range typ {
+		if unicode.IsLetter(r) {
+			return string(unicode.ToLower(r))
+		}
+	}
+	return "x"
+}
+
+// jsonGoTypes maps JSON Schema types to the Go types encoding/json decodes
+// them to.
+var jsonGoTypes = map[string]string{
+	"string":  "string",
+	"number":  "float64",
+	"integer": "int64",
+	"boolean": "bool",
+	"array":   "[]interface{}",
+	"object":  "map[string]interface{}",
+	"null":    "interface{}",
+}
+
+// GoType returns the name of a Go type for v, which is a reflect.Type, a
+// JSON Schema type name such as "integer" or "object", or any other value,
+// whose own type is returned.
+func GoType(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "interface{}"
+	case reflect.Type:
+		return v.String()
+	case string:
+		if t, ok := jsonGoTypes[v]; ok {
+			return t
+		}
+	}
+	return reflect.TypeOf(v).String()
+}
+
+// ZeroValue returns the Go expression for the zero value of the type named
+// typ: "0", `""`, "false", "nil", or a composite literal such as "T{}".
+func ZeroValue(typ string) string {
+	switch typ = strings.TrimSpace(typ); {
+	case typ == "string":
+		return `""`
+	case typ == "bool":
+		return "false"
+	case typ == "error" || typ == "any" ||
+		strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "[]") ||
+		strings.HasPrefix(typ, "map[") || strings.HasPrefix(typ, "chan") ||
+		strings.HasPrefix(typ, "<-chan") || strings.HasPrefix(typ, "func") ||
+		strings.HasPrefix(typ, "interface"):
+		return "nil"
+	}
+	switch typ {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64", "complex64", "complex128", "byte", "rune":
+		return "0"
+	}
+	return typ + "{}"
+}
+
+// GoComment wraps text to 80 columns as a Go comment, each line starting
+// with "// ". Blank lines separate paragraphs.
+func GoComment(text string) string {
+	var b strings.Builder
+	for i, para := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if i > 0 {
+			b.WriteString("//\n")
+		}
+		width := 0
+		for _, word := range strings.Fields(para) {
+			n := utf8.RuneCountInString(word)
+			switch {
+			case width == 0:
+				b.WriteString("// ")
+				width = 3
+			case width+1+n > goCommentWidth:
+				b.WriteString("\n// ")
+				width = 3
+			default:
+				b.WriteByte(' ')
+				width++
+			}
+			b.WriteString(word)
+			width += n
+		}
+		if width > 0 {
+			b.WriteByte('\n')
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+var (
+	importVersionRe = regexp.MustCompile(`^v[0-9]+$`)
+	identInvalidRe  = regexp.MustCompile(`[^\p{L}\p{Nd}_]+`)
+)
+
+// ImportAlias returns the package name conventionally used for the import
+// path: "github.com/x/go-yaml/v3" and "gopkg.in/yaml.v2" both give "yaml".
+func ImportAlias(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && importVersionRe.MatchString(name) {
+		name = parts[len(parts)-2]
+	}
+	if i := strings.Index(name, ".v"); i > 0 && importVersionRe.MatchString(name[i+1:]) {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(strings.TrimSuffix(name, "-go"), ".go")
+	name = strings.ToLower(identInvalidRe.ReplaceAllString(name, ""))
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "pkg" + name
+	}
+	return name
+}
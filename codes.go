@@ -0,0 +1,91 @@
+package funcmaps
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"image/png"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/code39"
+	"github.com/boombuler/barcode/code93"
+	"github.com/boombuler/barcode/datamatrix"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/qr"
+)
+
+// barcodeHeight is the height in pixels of rendered one-dimensional barcodes.
+const barcodeHeight = 80
+
+// Codes returns functions rendering QR codes and barcodes as PNG data URIs,
+// suitable for the src attribute of an img element.
+func Codes() FuncMap {
+	return FuncMap{
+		"qrcode":  QRCode,
+		"barcode": Barcode,
+	}
+}
+
+// QRCode returns a size x size pixel QR code image of data as a data URI.
+func QRCode(data string, size int) (template.URL, error) {
+	bc, err := qr.Encode(data, qr.M, qr.Auto)
+	if err != nil {
+		return "", err
+	}
+	return barcodeDataURI(bc, size, size)
+}
+
+// Barcode returns a barcode image of data as a data URI.
+//
+// Supported types are "code128", "code39", "code93", "ean" (EAN-8 or EAN-13,
+// depending on length), "datamatrix" and "qr".
+func Barcode(typ string, data string) (template.URL, error) {
+	var (
+		bc  barcode.Barcode
+		err error
+	)
+	twoD := false
+	switch strings.ToLower(typ) {
+	case "code128":
+		bc, err = code128.Encode(data)
+	case "code39":
+		bc, err = code39.Encode(data, false, true)
+	case "code93":
+		bc, err = code93.Encode(data, false, true)
+	case "ean", "ean8", "ean13":
+		bc, err = ean.Encode(data)
+	case "datamatrix":
+		bc, err = datamatrix.Encode(data)
+		twoD = true
+	case "qr", "qrcode":
+		bc, err = qr.Encode(data, qr.M, qr.Auto)
+		twoD = true
+	default:
+		return "", fmt.Errorf("unknown barcode type %q", typ)
+	}
+	if err != nil {
+		return "", err
+	}
+	width := bc.Bounds().Dx() * 2
+	height := barcodeHeight
+	if twoD {
+		height = bc.Bounds().Dy() * 8
+		width = bc.Bounds().Dx() * 8
+	}
+	return barcodeDataURI(bc, width, height)
+}
+
+func barcodeDataURI(bc barcode.Barcode, width, height int) (template.URL, error) {
+	scaled, err := barcode.Scale(bc, width, height)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scaled); err != nil {
+		return "", err
+	}
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())), nil
+}
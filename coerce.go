@@ -0,0 +1,128 @@
+package funcmaps
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cast"
+)
+
+// toText returns v as a string. Besides strings it accepts byte slices, as
+// read from files, databases and HTTP bodies, the html/template content
+// types, fmt.Stringer and error values, and numbers.
+func toText(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	}
+	return cast.ToStringE(v)
+}
+
+// textFunc adapts a string function to accept any value toText accepts.
+// Byte slices are copied into a string, unlike with textPredicate.
+func textFunc(f func(string) string) func(interface{}) (string, error) {
+	return func(v interface{}) (string, error) {
+		s, err := toText(v)
+		if err != nil {
+			return "", err
+		}
+		return f(s), nil
+	}
+}
+
+// textArgFunc adapts a string function taking an argument, such as a
+// cutset, to accept any value toText accepts as its subject, given last.
+func textArgFunc(f func(s, arg string) string) func(string, interface{}) (string, error) {
+	return func(arg string, v interface{}) (string, error) {
+		s, err := toText(v)
+		if err != nil {
+			return "", err
+		}
+		return f(s, arg), nil
+	}
+}
+
+// textPredicate adapts a pair of string and byte slice functions testing a
+// subject against an argument, using the byte slice function for byte
+// slices so that they are not copied.
+func textPredicate(fs func(s, arg string) bool, fb func(b, arg []byte) bool) func(string, interface{}) (bool, error) {
+	return func(arg string, v interface{}) (bool, error) {
+		if b, ok := v.([]byte); ok {
+			return fb(b, []byte(arg)), nil
+		}
+		s, err := toText(v)
+		if err != nil {
+			return false, err
+		}
+		return fs(s, arg), nil
+	}
+}
+
+// countText counts the non-overlapping instances of sub in v.
+func countText(sub string, v interface{}) (int, error) {
+	if b, ok := v.([]byte); ok {
+		return bytes.Count(b, []byte(sub)), nil
+	}
+	s, err := toText(v)
+	if err != nil {
+		return 0, err
+	}
+	return strings.Count(s, sub), nil
+}
+
+// wordCount counts the whitespace separated words in v.
+func wordCount(v interface{}) (int, error) {
+	if b, ok := v.([]byte); ok {
+		return len(bytes.Fields(b)), nil
+	}
+	s, err := toText(v)
+	if err != nil {
+		return 0, err
+	}
+	return len(strings.Fields(s)), nil
+}
+
+// byteVariants maps the code pointers of string functions to variants of
+// them accepting byte slices and the other values toText accepts, so that
+// Default keeps the plain types of its functions, as in
+// Default()["toLower"].(func(string) string).
+var (
+	byteVariantsMu sync.RWMutex
+	byteVariants   = map[uintptr]interface{}{}
+)
+
+// withBytes records variant as the variant of f used by AcceptBytes, and
+// returns f.
+func withBytes(f, variant interface{}) interface{} {
+	byteVariantsMu.Lock()
+	defer byteVariantsMu.Unlock()
+	byteVariants[reflect.ValueOf(f).Pointer()] = variant
+	return f
+}
+
+// AcceptBytes returns a copy of fm in which the string functions of the
+// package, such as toLower, trim and has_prefix, also accept byte slices,
+// as read from files, databases and HTTP bodies, and the other values
+// convertible to strings, such as numbers. Their byte slice arguments are
+// not copied when the functions only test them, as has_prefix and count
+// do. Functions are recognized by their code, whatever their names, but
+// not once wrapped, such as by Guard or Limit, so AcceptBytes should be
+// applied first.
+func AcceptBytes(fm FuncMap) FuncMap {
+	byteVariantsMu.RLock()
+	defer byteVariantsMu.RUnlock()
+	rs := make(FuncMap, len(fm))
+	for name, fn := range fm {
+		rs[name] = fn
+		if v := reflect.ValueOf(fn); v.Kind() == reflect.Func && !v.IsNil() {
+			if variant, ok := byteVariants[v.Pointer()]; ok {
+				rs[name] = variant
+			}
+		}
+	}
+	return rs
+}
@@ -0,0 +1,84 @@
+package funcmaps
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+var benchText = bytes.Repeat([]byte("The quick brown fox jumps over the lazy dog. "), 100)
+
+func BenchmarkHasPrefixString(b *testing.B) {
+	hasPrefix := textPredicate(strings.HasPrefix, bytes.HasPrefix)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		hasPrefix("The", string(benchText))
+	}
+}
+
+func BenchmarkHasPrefixBytes(b *testing.B) {
+	hasPrefix := textPredicate(strings.HasPrefix, bytes.HasPrefix)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		hasPrefix("The", benchText)
+	}
+}
+
+func BenchmarkCountString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		countText("fox", string(benchText))
+	}
+}
+
+func BenchmarkCountBytes(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		countText("fox", benchText)
+	}
+}
+
+func BenchmarkWordCountBytes(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		wordCount(benchText)
+	}
+}
+
+func BenchmarkToLowerBytes(b *testing.B) {
+	toLower := textFunc(strings.ToLower)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		toLower(benchText)
+	}
+}
+
+func TestDefaultPlainTypes(t *testing.T) {
+	fm := Default()
+	if _, ok := fm["toLower"].(func(string) string); !ok {
+		t.Errorf("toLower is %T, want func(string) string", fm["toLower"])
+	}
+	if _, ok := fm["has_prefix"].(func(string, string) bool); !ok {
+		t.Errorf("has_prefix is %T, want func(string, string) bool", fm["has_prefix"])
+	}
+}
+
+func TestAcceptBytes(t *testing.T) {
+	fm := AcceptBytes(Default())
+	for _, e := range []Example{
+		{Template: `{{toLower .}}`, Data: []byte("ABC"), Output: "abc"},
+		{Template: `{{lower .}}`, Data: []byte("ABC"), Output: "abc"},
+		{Template: `{{trim "-" .}}`, Data: []byte("-a-"), Output: "a"},
+		{Template: `{{has_prefix "a" .}}`, Data: []byte("abc"), Output: "true"},
+		{Template: `{{count "b" .}}`, Data: []byte("abcb"), Output: "2"},
+		{Template: `{{wc .}}`, Data: []byte("a b c"), Output: "3"},
+		{Template: `{{toUpper .}}`, Data: 42, Output: "42"},
+	} {
+		var buf bytes.Buffer
+		err := template.Must(template.New("").Funcs(template.FuncMap(fm)).Parse(e.Template)).Execute(&buf, e.Data)
+		if err != nil || buf.String() != e.Output {
+			t.Errorf("%s with %v: got %q, %v, want %q", e.Template, e.Data, buf.String(), err, e.Output)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package funcmaps
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Collections returns functions operating on slices, arrays and maps. The
+// set operations compare values as the eq function does, and intersect is
+// an alias of intersection.
+func Collections() FuncMap {
+	return addAliases(FuncMap{
+		"union":               Union,
+		"intersection":        Intersection,
+		"difference":          Difference,
+		"symmetricDifference": SymmetricDifference,
+		"frequencies":         Frequencies,
+		"topN":                TopN,
+		"columns":             Columns,
+		"transpose":           Transpose,
+		"flatten":             Flatten,
+		"zip":                 Zip,
+		"unzip":               Unzip,
+		"dictFromLists":       DictFromLists,
+		"paginate":            Paginate,
+		"sortNatural":         SortNatural,
+		"sortLocale":          SortLocale,
+	}, aliases)
+}
+
+// Union returns the values present in any of the lists, without duplicates,
+// in order of first appearance.
+func Union(lists ...interface{}) ([]interface{}, error) {
+	rs := make([]interface{}, 0)
+	for _, list := range lists {
+		values, err := listValues(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			if !has(reflect.ValueOf(rs), reflect.ValueOf(v)) {
+				rs = append(rs, v)
+			}
+		}
+	}
+	return rs, nil
+}
+
+// Intersection returns the values of a that are also in b, without duplicates.
+func Intersection(a, b interface{}) ([]interface{}, error) {
+	return filterValues(a, b, true)
+}
+
+// Difference returns the values of a that are not in b, without duplicates.
+func Difference(a, b interface{}) ([]interface{}, error) {
+	return filterValues(a, b, false)
+}
+
+// SymmetricDifference returns the values in either a or b but not in both,
+// without duplicates, those of a first.
+func SymmetricDifference(a, b interface{}) ([]interface{}, error) {
+	ab, err := filterValues(a, b, false)
+	if err != nil {
+		return nil, err
+	}
+	ba, err := filterValues(b, a, false)
+	if err != nil {
+		return nil, err
+	}
+	return append(ab, ba...), nil
+}
+
+// Frequency is a value and the number of times it occurs.
+type Frequency struct {
+	Value string
+	Count int
+}
+
+// Frequencies counts the occurrences of each value in list, keyed by the
+// string representation of the value.
+func Frequencies(list interface{}) (map[string]int, error) {
+	values, err := listValues(list)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]int, len(values))
+	for _, v := range values {
+		m[stringify(reflect.ValueOf(v))]++
+	}
+	return m, nil
+}
+
+// TopN returns the n most frequent values, most frequent first.
+// Ties are ordered by value. A negative n returns every value.
+func TopN(n int, freqs map[string]int) []Frequency {
+	rs := make([]Frequency, 0, len(freqs))
+	for v, c := range freqs {
+		rs = append(rs, Frequency{Value: v, Count: c})
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		if rs[i].Count != rs[j].Count {
+			return rs[i].Count > rs[j].Count
+		}
+		return rs[i].Value < rs[j].Value
+	})
+	if n >= 0 && n < len(rs) {
+		rs = rs[:n]
+	}
+	return rs
+}
+
+// Columns arranges items into rows of at most n cells, filling the grid
+// column by column, so that reading each column top to bottom keeps the
+// original order.
+//
+//	columns 3 [1 2 3 4 5 6 7] => [[1 4 7] [2 5] [3 6]]
+func Columns(n int, items interface{}) ([][]interface{}, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("columns: invalid column count %d", n)
+	}
+	values, err := listValues(items)
+	if err != nil {
+		return nil, err
+	}
+	nrows := (len(values) + n - 1) / n
+	rows := make([][]interface{}, nrows)
+	for i, v := range values {
+		rows[i%nrows] = append(rows[i%nrows], v)
+	}
+	return rows, nil
+}
+
+// Transpose swaps the rows and columns of a slice of slices.
+// Rows may have different lengths; missing cells are skipped.
+func Transpose(rows interface{}) ([][]interface{}, error) {
+	outer, err := listValues(rows)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([][]interface{}, 0)
+	for _, row := range outer {
+		cells, err := listValues(row)
+		if err != nil {
+			return nil, err
+		}
+		for j, cell := range cells {
+			if j == len(rs) {
+				rs = append(rs, nil)
+			}
+			rs[j] = append(rs[j], cell)
+		}
+	}
+	return rs, nil
+}
+
+// Flatten concatenates a slice of slices into a single slice.
+func Flatten(rows interface{}) ([]interface{}, error) {
+	outer, err := listValues(rows)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]interface{}, 0)
+	for _, row := range outer {
+		cells, err := listValues(row)
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, cells...)
+	}
+	return rs, nil
+}
+
+// Zip returns the tuples of the elements at the same index of each list,
+// as many as the shortest list has, such as for tables built from parallel
+// slices:
+//
+//	zip [a b c] [1 2] => [[a 1] [b 2]]
+//
+//	{{range zip .Names .Ages}}<tr><td>{{index . 0}}<td>{{index . 1}}{{end}}
+func Zip(lists ...interface{}) ([][]interface{}, error) {
+	if len(lists) == 0 {
+		return [][]interface{}{}, nil
+	}
+	cols := make([][]interface{}, len(lists))
+	n := -1
+	for i, list := range lists {
+		values, err := listValues(list)
+		if err != nil {
+			return nil, err
+		}
+		cols[i] = values
+		if n < 0 || len(values) < n {
+			n = len(values)
+		}
+	}
+	rs := make([][]interface{}, 0, n)
+	for j := 0; j < n; j++ {
+		tuple := make([]interface{}, len(cols))
+		for i, col := range cols {
+			tuple[i] = col[j]
+		}
+		rs = append(rs, tuple)
+	}
+	return rs, nil
+}
+
+// Unzip is the reverse of Zip, returning a list of the first elements of
+// the tuples, a list of the second elements, and so on. The tuples must
+// have the same length.
+//
+//	unzip [[a 1] [b 2]] => [[a b] [1 2]]
+func Unzip(tuples interface{}) ([][]interface{}, error) {
+	outer, err := listValues(tuples)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([][]interface{}, 0)
+	for i, tuple := range outer {
+		values, err := listValues(tuple)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			for range values {
+				rs = append(rs, make([]interface{}, 0, len(outer)))
+			}
+		} else if len(values) != len(rs) {
+			return nil, fmt.Errorf("unzip: tuple %d has %d elements, want %d", i, len(values), len(rs))
+		}
+		for j, v := range values {
+			rs[j] = append(rs[j], v)
+		}
+	}
+	return rs, nil
+}
+
+// DictFromLists returns a map of each key to the value at the same index,
+// keyed by the string representation of the keys. The lists must have the
+// same length.
+//
+//	dictFromLists [a b] [1 2] => map[a:1 b:2]
+func DictFromLists(keys, values interface{}) (map[string]interface{}, error) {
+	ks, err := listValues(keys)
+	if err != nil {
+		return nil, err
+	}
+	vs, err := listValues(values)
+	if err != nil {
+		return nil, err
+	}
+	if len(ks) != len(vs) {
+		return nil, fmt.Errorf("dictFromLists: %d keys but %d values", len(ks), len(vs))
+	}
+	m := make(map[string]interface{}, len(ks))
+	for i, k := range ks {
+		m[stringify(reflect.ValueOf(k))] = vs[i]
+	}
+	return m, nil
+}
+
+// filterValues returns the distinct values of a whose presence in b is keep.
+func filterValues(a, b interface{}, keep bool) ([]interface{}, error) {
+	values, err := listValues(a)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := listValues(b); err != nil {
+		return nil, err
+	}
+	other := reflect.ValueOf(b)
+	rs := make([]interface{}, 0)
+	for _, v := range values {
+		if has(other, reflect.ValueOf(v)) != keep {
+			continue
+		}
+		if !has(reflect.ValueOf(rs), reflect.ValueOf(v)) {
+			rs = append(rs, v)
+		}
+	}
+	return rs, nil
+}
+
+// listValues returns the elements of a slice or array, or the values of a map.
+// A nil value is treated as an empty list.
+func listValues(list interface{}) ([]interface{}, error) {
+	v, isNil := indirect(reflect.ValueOf(list))
+	if isNil || !v.IsValid() {
+		return nil, nil
+	}
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice:
+		rs := make([]interface{}, v.Len())
+		for i := range rs {
+			rs[i] = v.Index(i).Interface()
+		}
+		return rs, nil
+	case reflect.Map:
+		rs := make([]interface{}, 0, v.Len())
+		r := v.MapRange()
+		for r.Next() {
+			rs = append(rs, r.Value().Interface())
+		}
+		return rs, nil
+	}
+	return nil, fmt.Errorf("expected slice, array or map, got %s", v.Type())
+}
+
+// keyValue returns the value of the map key or struct field key of v.
+func keyValue(v interface{}, key string) (interface{}, bool) {
+	rv, isNil := indirect(reflect.ValueOf(v))
+	if isNil || !rv.IsValid() {
+		return nil, false
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		e := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()))
+		if !e.IsValid() {
+			return nil, false
+		}
+		return e.Interface(), true
+	case reflect.Struct:
+		f, ok := rv.Type().FieldByName(key)
+		if !ok || f.PkgPath != "" {
+			return nil, false
+		}
+		return rv.FieldByIndex(f.Index).Interface(), true
+	}
+	return nil, false
+}
@@ -0,0 +1,193 @@
+package funcmaps
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Colors returns functions for computing CSS colors from a brand color.
+//
+// Colors are accepted as "#rgb" or "#rrggbb" (the leading # is optional)
+// and returned as lowercase "#rrggbb".
+func Colors() FuncMap {
+	return FuncMap{
+		"hexToRGB":      HexToRGB,
+		"rgbToHex":      RGBToHex,
+		"lighten":       Lighten,
+		"darken":        Darken,
+		"mix":           Mix,
+		"contrastColor": ContrastColor,
+	}
+}
+
+// RGB is a color with 8-bit red, green and blue channels.
+type RGB struct {
+	R, G, B uint8
+}
+
+// String returns the color in CSS functional notation, eg. "rgb(255, 0, 0)".
+func (c RGB) String() string {
+	return fmt.Sprintf("rgb(%d, %d, %d)", c.R, c.G, c.B)
+}
+
+// Hex returns the color as "#rrggbb".
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// HexToRGB parses a "#rgb" or "#rrggbb" color.
+func HexToRGB(s string) (RGB, error) {
+	h := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(h) == 3 {
+		h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+	}
+	if len(h) != 6 {
+		return RGB{}, fmt.Errorf("invalid hex color %q", s)
+	}
+	n, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return RGB{}, fmt.Errorf("invalid hex color %q", s)
+	}
+	return RGB{R: uint8(n >> 16), G: uint8(n >> 8), B: uint8(n)}, nil
+}
+
+// RGBToHex returns "#rrggbb" for the given channels, clamped to 0-255.
+func RGBToHex(r, g, b int) string {
+	return RGB{R: clampByte(float64(r)), G: clampByte(float64(g)), B: clampByte(float64(b))}.Hex()
+}
+
+// Lighten increases the lightness of color by pct percent (0-100).
+func Lighten(pct float64, color string) (string, error) {
+	return adjustLightness(color, pct/100)
+}
+
+// Darken decreases the lightness of color by pct percent (0-100).
+func Darken(pct float64, color string) (string, error) {
+	return adjustLightness(color, -pct/100)
+}
+
+// Mix blends two colors, using pct percent (0-100) of a and the rest of b.
+func Mix(pct float64, a, b string) (string, error) {
+	ca, err := HexToRGB(a)
+	if err != nil {
+		return "", err
+	}
+	cb, err := HexToRGB(b)
+	if err != nil {
+		return "", err
+	}
+	w := math.Max(0, math.Min(1, pct/100))
+	mixed := RGB{
+		R: clampByte(float64(ca.R)*w + float64(cb.R)*(1-w)),
+		G: clampByte(float64(ca.G)*w + float64(cb.G)*(1-w)),
+		B: clampByte(float64(ca.B)*w + float64(cb.B)*(1-w)),
+	}
+	return mixed.Hex(), nil
+}
+
+// ContrastColor returns "#000000" or "#ffffff", whichever is more readable
+// on top of color, based on its relative luminance (WCAG 2.0).
+func ContrastColor(color string) (string, error) {
+	c, err := HexToRGB(color)
+	if err != nil {
+		return "", err
+	}
+	// black text wins when contrast against black beats contrast against white:
+	// (L + 0.05) / 0.05 > 1.05 / (L + 0.05)
+	if luminance(c) > math.Sqrt(1.05*0.05)-0.05 {
+		return "#000000", nil
+	}
+	return "#ffffff", nil
+}
+
+func adjustLightness(color string, delta float64) (string, error) {
+	c, err := HexToRGB(color)
+	if err != nil {
+		return "", err
+	}
+	h, s, l := rgbToHSL(c)
+	l = math.Max(0, math.Min(1, l+delta))
+	return hslToRGB(h, s, l).Hex(), nil
+}
+
+// luminance returns the relative luminance of c, from 0 (black) to 1 (white).
+func luminance(c RGB) float64 {
+	channel := func(v uint8) float64 {
+		f := float64(v) / 255
+		if f <= 0.03928 {
+			return f / 12.92
+		}
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.R) + 0.7152*channel(c.G) + 0.0722*channel(c.B)
+}
+
+func rgbToHSL(c RGB) (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	l = (max + min) / 2
+	if max == min {
+		return 0, 0, l
+	}
+	d := max - min
+	if l > 0.5 {
+		s = d / (2 - max - min)
+	} else {
+		s = d / (max + min)
+	}
+	switch max {
+	case r:
+		h = (g - b) / d
+		if g < b {
+			h += 6
+		}
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return h / 6, s, l
+}
+
+func hslToRGB(h, s, l float64) RGB {
+	if s == 0 {
+		v := clampByte(l * 255)
+		return RGB{R: v, G: v, B: v}
+	}
+	var q float64
+	if l < 0.5 {
+		q = l * (1 + s)
+	} else {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+	hue := func(t float64) float64 {
+		if t < 0 {
+			t++
+		}
+		if t > 1 {
+			t--
+		}
+		switch {
+		case t < 1.0/6:
+			return p + (q-p)*6*t
+		case t < 0.5:
+			return q
+		case t < 2.0/3:
+			return p + (q-p)*(2.0/3-t)*6
+		}
+		return p
+	}
+	return RGB{
+		R: clampByte(hue(h+1.0/3) * 255),
+		G: clampByte(hue(h) * 255),
+		B: clampByte(hue(h-1.0/3) * 255),
+	}
+}
+
+func clampByte(f float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, math.Round(f))))
+}
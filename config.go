@@ -0,0 +1,148 @@
+package funcmaps
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Source is a source of configuration values, looked up by dotted keys
+// such as "db.host".
+type Source interface {
+	Lookup(key string) (interface{}, bool)
+}
+
+// NewConfigFuncs returns functions looking up configuration values in
+// sources, which take precedence in the order given:
+//
+//	conf "db.host" "localhost"
+//	hasConf "db.host"
+//
+// The default of conf is returned when no source has the key; without a
+// default, a missing key is an error.
+func NewConfigFuncs(sources ...Source) FuncMap {
+	return recordRisks(FuncMap{
+		"conf": func(key string, def ...interface{}) (interface{}, error) {
+			if v, ok := lookupConfig(sources, key); ok {
+				return v, nil
+			}
+			if len(def) > 0 {
+				return def[0], nil
+			}
+			return nil, fmt.Errorf("conf: missing key %q", key)
+		},
+		"hasConf": func(key string) bool {
+			_, ok := lookupConfig(sources, key)
+			return ok
+		},
+	})
+}
+
+func lookupConfig(sources []Source, key string) (interface{}, bool) {
+	for _, src := range sources {
+		if v, ok := src.Lookup(key); ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// EnvSource returns a Source looking up keys in the environment, with
+// prefix prepended, dots and dashes replaced by underscores, and upper
+// cased: with the prefix "APP_", "db.host" is read from APP_DB_HOST.
+func EnvSource(prefix string) Source {
+	return envSource(prefix)
+}
+
+type envSource string
+
+var envKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+func (prefix envSource) Lookup(key string) (interface{}, bool) {
+	return os.LookupEnv(string(prefix) + strings.ToUpper(envKeyReplacer.Replace(key)))
+}
+
+// MapSource returns a Source looking up keys in m. The parts of a dotted
+// key index nested maps, and slices by position: "servers.0.name".
+func MapSource(m map[string]interface{}) Source {
+	return mapSource(m)
+}
+
+type mapSource map[string]interface{}
+
+func (m mapSource) Lookup(key string) (interface{}, bool) {
+	var v interface{} = map[string]interface{}(m)
+	for _, part := range strings.Split(key, ".") {
+		rv, _ := indirect(reflect.ValueOf(v))
+		switch {
+		case !rv.IsValid():
+			return nil, false
+		case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+			e := rv.MapIndex(reflect.ValueOf(part).Convert(rv.Type().Key()))
+			if !e.IsValid() {
+				return nil, false
+			}
+			v = e.Interface()
+		case rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= rv.Len() {
+				return nil, false
+			}
+			v = rv.Index(i).Interface()
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// FileSource returns a Source looking up keys in the JSON or YAML file
+// name of fsys, as MapSource does. The format is chosen by the extension:
+// ".json", ".yaml" or ".yml".
+func FileSource(fsys fs.FS, name string) (Source, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	switch ext := strings.ToLower(path.Ext(name)); ext {
+	case ".json":
+		err = json.Unmarshal(b, &m)
+	case ".yaml", ".yml":
+		var raw map[interface{}]interface{}
+		if err = yaml.Unmarshal(b, &raw); err == nil {
+			m = stringKeys(raw).(map[string]interface{})
+		}
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported format %q", name, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", name, err)
+	}
+	return mapSource(m), nil
+}
+
+// stringKeys converts the maps decoded by yaml to map[string]interface{},
+// recursively, so they are usable like the maps decoded by encoding/json.
+func stringKeys(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = stringKeys(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = stringKeys(e)
+		}
+	}
+	return v
+}
@@ -0,0 +1,91 @@
+package funcmaps
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// Convert returns functions converting values between types, such as form
+// values and configuration read as strings:
+//
+//	toInt "42"        => 42
+//	toFloat "1.5"     => 1.5
+//	toBool "true"     => true
+//	toString 42       => "42"
+//	toStrings [1 2]   => ["1" "2"]
+//
+// Each returns the zero value for values it cannot convert, and has a
+// must variant, such as mustToInt, returning an error instead. Strings are
+// parsed as decimal numbers, so that "010" is 10.
+func Convert() FuncMap {
+	return FuncMap{
+		"toInt":         func(v interface{}) int { n, _ := ToInt(v); return n },
+		"toInt64":       func(v interface{}) int64 { n, _ := ToInt64(v); return n },
+		"toFloat":       func(v interface{}) float64 { f, _ := ToFloat(v); return f },
+		"toBool":        func(v interface{}) bool { b, _ := ToBool(v); return b },
+		"toString":      func(v interface{}) string { s, _ := ToString(v); return s },
+		"toStrings":     func(v interface{}) []string { ss, _ := ToStrings(v); return ss },
+		"mustToInt":     ToInt,
+		"mustToInt64":   ToInt64,
+		"mustToFloat":   ToFloat,
+		"mustToBool":    ToBool,
+		"mustToString":  ToString,
+		"mustToStrings": ToStrings,
+	}
+}
+
+// ToInt converts v to an int.
+func ToInt(v interface{}) (int, error) {
+	if s, ok := v.(string); ok {
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 0)
+		return int(n), err
+	}
+	return cast.ToIntE(v)
+}
+
+// ToInt64 converts v to an int64.
+func ToInt64(v interface{}) (int64, error) {
+	if s, ok := v.(string); ok {
+		return strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	}
+	return cast.ToInt64E(v)
+}
+
+// ToFloat converts v to a float64.
+func ToFloat(v interface{}) (float64, error) {
+	if s, ok := v.(string); ok {
+		return strconv.ParseFloat(strings.TrimSpace(s), 64)
+	}
+	return cast.ToFloat64E(v)
+}
+
+// ToBool converts v to a bool. Strings are parsed by strconv.ParseBool, and
+// numbers are true unless zero.
+func ToBool(v interface{}) (bool, error) {
+	if s, ok := v.(string); ok {
+		return strconv.ParseBool(strings.TrimSpace(s))
+	}
+	return cast.ToBoolE(v)
+}
+
+// ToString converts v to a string, as toText does.
+func ToString(v interface{}) (string, error) {
+	return toText(v)
+}
+
+// ToStrings converts the elements of a slice, array or map to strings.
+func ToStrings(v interface{}) ([]string, error) {
+	values, err := listValues(v)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]string, len(values))
+	for i, e := range values {
+		if rs[i], err = toText(e); err != nil {
+			return nil, err
+		}
+	}
+	return rs, nil
+}
@@ -0,0 +1,40 @@
+package funcmaps
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	for _, tt := range []struct {
+		text, want string
+	}{
+		{`{{toInt "010"}}`, "10"},
+		{`{{toInt " 42 "}}`, "42"},
+		{`{{toInt "x"}}`, "0"},
+		{`{{toFloat "1.5"}}`, "1.5"},
+		{`{{toBool "true"}}`, "true"},
+		{`{{toString 42}}`, "42"},
+		{`{{toStrings .}}`, "[1 2]"},
+	} {
+		got, err := execute(Convert(), tt.text, []int{1, 2})
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v, want %q", tt.text, got, err, tt.want)
+		}
+	}
+	for _, text := range []string{`{{mustToInt "x"}}`, `{{mustToFloat "x"}}`, `{{mustToBool "maybe"}}`, `{{mustToInt64 "9223372036854775808"}}`} {
+		if _, err := execute(Convert(), text, nil); err == nil {
+			t.Errorf("%s: got nil error", text)
+		}
+	}
+}
+
+func TestToStrings(t *testing.T) {
+	got, err := ToStrings([]interface{}{1, "a", true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1", "a", "true"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
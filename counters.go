@@ -0,0 +1,43 @@
+package funcmaps
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Counters is a StateFuncs adding cycle and counter, whose positions start
+// over with every execution:
+//
+//	t := funcmaps.New(fm, funcmaps.WithState(funcmaps.Counters))
+//
+//	{{range .Rows}}<tr class="{{cycle "odd" "even"}}">{{end}}
+//	{{range .Steps}}<h2>Step {{counter "steps"}}</h2>{{end}}
+//
+// cycle returns its arguments in turn, one per call, with a separate
+// position for every different list of arguments. counter returns 1, 2, 3
+// and so on, counting the calls with the same name.
+func Counters(st *ExecState) FuncMap {
+	return FuncMap{
+		"cycle": func(values ...interface{}) (interface{}, error) {
+			if len(values) == 0 {
+				return nil, fmt.Errorf("cycle: no values")
+			}
+			keys := make([]string, len(values))
+			for i, v := range values {
+				keys[i] = fmt.Sprintf("%q", fmt.Sprint(v))
+			}
+			n := st.Update("funcmaps.cycle."+strings.Join(keys, ","), func(v interface{}, ok bool) interface{} {
+				n, _ := v.(int)
+				return n + 1
+			})
+			return values[(n.(int)-1)%len(values)], nil
+		},
+		"counter": func(name string) int {
+			n := st.Update("funcmaps.counter."+name, func(v interface{}, ok bool) interface{} {
+				n, _ := v.(int)
+				return n + 1
+			})
+			return n.(int)
+		},
+	}
+}
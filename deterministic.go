@@ -0,0 +1,96 @@
+package funcmaps
+
+import (
+	"math/rand"
+	"time"
+)
+
+// The functions of Deterministic have these types, so that Purity
+// recognizes them whatever their names, and after wrapping by Guard or
+// Limit, which keep the types of the functions they wrap.
+type (
+	fixedTimeFunc   func() time.Time
+	fixedStringFunc func() string
+	fixedEnvFunc    func(string) string
+	seededIDFunc    func() (string, error)
+	seededSizedFunc func(int) (string, error)
+	fixedDateFunc   func(layout, zone string, date interface{}) string
+	fixedLocaleFunc func(layout, zone, lang string, date interface{}) string
+)
+
+// Deterministic returns replacements for the functions of Default and IDs
+// whose results depend on more than their arguments, for reproducible
+// builds such as of static sites:
+//
+//	fm := funcmaps.Combined(funcmaps.Default(), funcmaps.IDs(), funcmaps.Deterministic(1, buildTime))
+//
+// now and NOW return fixedTime, date and date_locale format fixedTime in
+// place of values other than times, env returns "", and uuid and the IDs
+// generators use fixedTime and random numbers from seed. Generated IDs
+// depend on the order of the calls, so templates executed concurrently
+// should each get their own Deterministic functions.
+func Deterministic(seed int64, fixedTime time.Time) FuncMap {
+	g := &idGenerator{
+		rand: rand.New(rand.NewSource(seed)),
+		now:  func() time.Time { return fixedTime },
+	}
+	orFixed := func(date interface{}) time.Time {
+		if t, ok := toTime(date); ok {
+			return t
+		}
+		return fixedTime
+	}
+	return FuncMap{
+		"now": fixedTimeFunc(func() time.Time { return fixedTime }),
+		"date": fixedDateFunc(func(layout, zone string, date interface{}) string {
+			return formateDate(layout, orFixed(date), zone, fixedTime.Location(), timeLocale("en"))
+		}),
+		"date_locale": fixedLocaleFunc(func(layout, zone, lang string, date interface{}) string {
+			return formateDate(layout, orFixed(date), zone, fixedTime.Location(), timeLocale(lang))
+		}),
+		"NOW": fixedStringFunc(func() string { return fixedTime.String() }),
+		"env": fixedEnvFunc(func(string) string { return "" }),
+		"uuid": fixedStringFunc(func() string {
+			s, _ := g.uuidv4() // reading from math/rand never fails
+			return s
+		}),
+		"uuidv4":  seededIDFunc(g.uuidv4),
+		"uuidv7":  seededIDFunc(g.uuidv7),
+		"ulid":    seededIDFunc(g.ulid),
+		"nanoid":  seededSizedFunc(g.nanoid),
+		"shortid": seededIDFunc(g.shortid),
+	}
+}
+
+// isDeterministic reports whether fn is a function of Deterministic.
+func isDeterministic(fn interface{}) bool {
+	switch fn.(type) {
+	case fixedTimeFunc, fixedStringFunc, fixedEnvFunc, seededIDFunc, seededSizedFunc,
+		fixedDateFunc, fixedLocaleFunc:
+		return true
+	}
+	return false
+}
+
+// Purity returns the functions of fm whose results depend on more than
+// their arguments, such as on the clock, the environment, files or the
+// network, as classified by Audit with known. Functions of Deterministic
+// are not included, so that a static-site build can check that its output
+// is reproducible:
+//
+//	if impure := funcmaps.Purity(fm); len(impure) > 0 {
+//		log.Fatalf("output is not reproducible: %v", impure.Names())
+//	}
+func Purity(fm FuncMap, known ...map[string]Risk) AuditReport {
+	var impure AuditReport
+	for _, e := range Audit(fm, known...) {
+		if e.Risk < RiskReadsEnv || e.Risk > RiskNetwork {
+			continue
+		}
+		if isDeterministic(fm[e.Name]) {
+			continue
+		}
+		impure = append(impure, e)
+	}
+	return impure
+}
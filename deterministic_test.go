@@ -0,0 +1,24 @@
+package funcmaps
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestDeterministicDate(t *testing.T) {
+	fixed := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	fm := Combined(Default(), Deterministic(1, fixed))
+	var buf bytes.Buffer
+	tmpl := template.Must(template.New("").Funcs(template.FuncMap(fm)).Parse(`{{date "2006-01-02" "" nil}} {{date_locale "January" "" "fr" .}}`))
+	if err := tmpl.Execute(&buf, "not a time"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "2024-03-01 mars"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if impure := Purity(fm); len(impure) > 0 {
+		t.Errorf("Purity: got %v", impure.Names())
+	}
+}
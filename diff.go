@@ -0,0 +1,247 @@
+package funcmaps
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around changes in diff.
+const diffContext = 3
+
+// Diff returns functions showing the differences between two texts, for
+// audit logs and "what changed" emails.
+func Diff() FuncMap {
+	return FuncMap{
+		"diff":     UnifiedDiff,
+		"diffHTML": DiffHTML,
+	}
+}
+
+// diffOp is an operation of an edit script: ' ' keeps, '-' deletes and '+'
+// inserts text.
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// UnifiedDiff returns the line differences from a to b in unified diff
+// format, or "" if they are equal.
+func UnifiedDiff(a, b string) string {
+	if a == b {
+		return ""
+	}
+	ops := diffStrings(splitLines(a), splitLines(b))
+	var sb strings.Builder
+	sb.WriteString("--- a\n+++ b\n")
+	for start := 0; start < len(ops); {
+		// find the next change, and extend the hunk while changes are close
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		end, gap := first, 0
+		for i := first; i < len(ops) && gap <= 2*diffContext; i++ {
+			if ops[i].kind == ' ' {
+				gap++
+			} else {
+				end, gap = i+1, 0
+			}
+		}
+		from, to := max0(first-diffContext), end+diffContext
+		if to > len(ops) {
+			to = len(ops)
+		}
+		aLine, bLine := 1, 1
+		for _, op := range ops[:from] {
+			if op.kind != '+' {
+				aLine++
+			}
+			if op.kind != '-' {
+				bLine++
+			}
+		}
+		aCount, bCount := 0, 0
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aLine, aCount), hunkRange(bLine, bCount))
+		for _, op := range ops[from:to] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.text)
+			sb.WriteByte('\n')
+		}
+		start = to
+	}
+	return sb.String()
+}
+
+func max0(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// hunkRange formats the start and length of a hunk; empty ranges start at
+// the line before them.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+var diffWordRe = regexp.MustCompile(`\s+|[^\s]+`)
+
+// DiffHTML returns the word differences from a to b as HTML, with deleted
+// text in del elements and inserted text in ins elements. All text is
+// escaped.
+func DiffHTML(a, b string) template.HTML {
+	ops := diffStrings(diffWordRe.FindAllString(a, -1), diffWordRe.FindAllString(b, -1))
+	var sb strings.Builder
+	for i := 0; i < len(ops); {
+		kind := ops[i].kind
+		var run strings.Builder
+		for ; i < len(ops) && ops[i].kind == kind; i++ {
+			run.WriteString(ops[i].text)
+		}
+		text := html.EscapeString(run.String())
+		switch kind {
+		case '-':
+			sb.WriteString("<del>" + text + "</del>")
+		case '+':
+			sb.WriteString("<ins>" + text + "</ins>")
+		default:
+			sb.WriteString(text)
+		}
+	}
+	return template.HTML(sb.String())
+}
+
+// diffStrings returns a shortest edit script from a to b, using the linear
+// space variant of the algorithm of Myers' "An O(ND) Difference Algorithm
+// and Its Variations": the middle of the edit script is found by searching
+// from both ends at once, and both halves are diffed recursively.
+func diffStrings(a, b []string) []diffOp {
+	var rs []diffOp
+	// common prefix and suffix
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		rs = append(rs, diffOp{' ', a[0]})
+		a, b = a[1:], b[1:]
+	}
+	var suffix []diffOp
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		suffix = append(suffix, diffOp{' ', a[len(a)-1]})
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+	if len(a) == 0 || len(b) == 0 {
+		rs = appendChanges(rs, a, b)
+	} else if x, y, ok := middleSnake(a, b); ok {
+		rs = append(rs, diffStrings(a[:x], b[:y])...)
+		rs = append(rs, diffStrings(a[x:], b[y:])...)
+	} else {
+		rs = appendChanges(rs, a, b)
+	}
+	for i := len(suffix) - 1; i >= 0; i-- {
+		rs = append(rs, suffix[i])
+	}
+	return rs
+}
+
+// appendChanges appends the deletion of a and the insertion of b to rs.
+func appendChanges(rs []diffOp, a, b []string) []diffOp {
+	for _, s := range a {
+		rs = append(rs, diffOp{'-', s})
+	}
+	for _, s := range b {
+		rs = append(rs, diffOp{'+', s})
+	}
+	return rs
+}
+
+// middleSnake returns a point of a shortest edit script from a to b where
+// the searches from the start and from the end meet, splitting the script
+// in two halves. a and b must not be empty.
+func middleSnake(a, b []string) (int, int, bool) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	off := maxD
+	// furthest x reached on each diagonal k, forward in vf and backward,
+	// counted from the ends, in vb
+	vf, vb := make([]int, 2*maxD+2), make([]int, 2*maxD+2)
+	for i := range vf {
+		vf[i], vb[i] = -1, -1
+	}
+	vf[off+1], vb[off+1] = 0, 0
+	delta := n - m
+	// with an odd delta the searches meet during a forward step
+	front := delta%2 != 0
+	// trims of the diagonals run off the edit graph
+	kfStart, kfEnd, kbStart, kbEnd := 0, 0, 0, 0
+	for d := 0; d < maxD; d++ {
+		for k := -d + kfStart; k <= d-kfEnd; k += 2 {
+			var x int
+			if k == -d || k != d && vf[off+k-1] < vf[off+k+1] {
+				x = vf[off+k+1]
+			} else {
+				x = vf[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			vf[off+k] = x
+			switch kb := off + delta - k; {
+			case x > n:
+				kfEnd += 2
+			case y > m:
+				kfStart += 2
+			case front && kb >= 0 && kb < len(vb) && vb[kb] != -1 && x >= n-vb[kb]:
+				return x, y, true
+			}
+		}
+		for k := -d + kbStart; k <= d-kbEnd; k += 2 {
+			var x int
+			if k == -d || k != d && vb[off+k-1] < vb[off+k+1] {
+				x = vb[off+k+1]
+			} else {
+				x = vb[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x, y = x+1, y+1
+			}
+			vb[off+k] = x
+			switch kf := off + delta - k; {
+			case x > n:
+				kbEnd += 2
+			case y > m:
+				kbStart += 2
+			case !front && kf >= 0 && kf < len(vf) && vf[kf] != -1 && vf[kf] >= n-x:
+				return vf[kf], vf[kf] - (kf - off), true
+			}
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,58 @@
+package funcmaps
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	if got := UnifiedDiff("a\nb\n", "a\nb\n"); got != "" {
+		t.Errorf("equal texts: got %q", got)
+	}
+	got := UnifiedDiff("a\nb\nc\n", "a\nx\nc\n")
+	want := "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDiffStringsMinimal(t *testing.T) {
+	for _, tt := range []struct {
+		a, b    string
+		changes int
+	}{
+		{"abcabba", "cbabac", 5},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"abc", "abc", 0},
+		{"abcdef", "abxdef", 2},
+	} {
+		ops := diffStrings(strings.Split(tt.a, ""), strings.Split(tt.b, ""))
+		var a, b strings.Builder
+		changes := 0
+		for _, op := range ops {
+			if op.kind != '+' {
+				a.WriteString(op.text)
+			}
+			if op.kind != '-' {
+				b.WriteString(op.text)
+			}
+			if op.kind != ' ' {
+				changes++
+			}
+		}
+		if a.String() != tt.a || b.String() != tt.b {
+			t.Errorf("%q to %q: script gives %q to %q", tt.a, tt.b, a.String(), b.String())
+		}
+		if changes != tt.changes {
+			t.Errorf("%q to %q: %d changes, want %d", tt.a, tt.b, changes, tt.changes)
+		}
+	}
+}
+
+func TestDiffHTML(t *testing.T) {
+	got := DiffHTML("the <b> cat", "the <b> dog")
+	if want := "the &lt;b&gt; <del>cat</del><ins>dog</ins>"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
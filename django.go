@@ -0,0 +1,213 @@
+package funcmaps
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Django returns functions named after the Django and Jinja2 filters they
+// implement, for templates ported from Python. Filter arguments come
+// first, so that {{value|truncatechars:9}} becomes
+// {{.Value | truncatechars 9}}.
+//
+// Like Django's filter, safe returns its argument as template.HTML, which
+// html/template does not escape: use of this funcmap presents a security
+// risk unless the values marked safe come from a trusted source.
+func Django() FuncMap {
+	return recordRisks(FuncMap{
+		"default":       IsDefault,
+		"length":        Length,
+		"lower":         textFunc(strings.ToLower),
+		"upper":         textFunc(strings.ToUpper),
+		"truncatechars": TruncateChars,
+		"date":          DjangoDate,
+		"safe":          HTML,
+		"striptags":     textFunc(StripTags),
+		"linebreaksbr":  LinebreaksBR,
+	})
+}
+
+// Length returns the number of elements of a slice, array, map or channel,
+// or of characters of a string, or 0 for other values.
+func Length(v interface{}) int {
+	if s, ok := v.(string); ok {
+		return utf8.RuneCountInString(s)
+	}
+	rv, _ := indirect(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return rv.Len()
+	case reflect.String:
+		return utf8.RuneCountInString(rv.String())
+	}
+	return 0
+}
+
+// TruncateChars truncates s to n user-perceived characters, the last of
+// which is "…" if s was truncated.
+func TruncateChars(n int, s interface{}) (string, error) {
+	text, err := toText(s)
+	if err != nil || GraphemeLen(text) <= n {
+		return text, err
+	}
+	if n <= 0 {
+		return "", nil
+	}
+	return GraphemeTrunc(n-1, text) + "…", nil
+}
+
+// LinebreaksBR escapes s and replaces its line breaks with <br>.
+func LinebreaksBR(s interface{}) (template.HTML, error) {
+	text, err := toText(s)
+	if err != nil {
+		return "", err
+	}
+	text = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(text)
+	return template.HTML(strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")), nil
+}
+
+var apMonths = [...]string{"Jan.", "Feb.", "March", "April", "May", "June", "July", "Aug.", "Sept.", "Oct.", "Nov.", "Dec."}
+
+// DjangoDate formats date, as accepted by FormatTime, with a Django date
+// format such as "N j, Y" or "D, d M Y H:i". A backslash escapes the next
+// character. The time keeps its own location. Values other than times,
+// such as nil, give "", as with Django.
+func DjangoDate(format string, date interface{}) string {
+	t, ok := toTime(date)
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	hour12 := t.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		switch c {
+		case '\\':
+			if i+1 < len(format) {
+				i++
+				b.WriteByte(format[i])
+			}
+		case 'a':
+			if t.Hour() < 12 {
+				b.WriteString("a.m.")
+			} else {
+				b.WriteString("p.m.")
+			}
+		case 'A':
+			b.WriteString(t.Format("PM"))
+		case 'b':
+			b.WriteString(strings.ToLower(t.Format("Jan")))
+		case 'c':
+			b.WriteString(t.Format("2006-01-02T15:04:05.999999-07:00"))
+		case 'd':
+			b.WriteString(t.Format("02"))
+		case 'D':
+			b.WriteString(t.Format("Mon"))
+		case 'e':
+			b.WriteString(t.Location().String())
+		case 'E', 'F':
+			b.WriteString(t.Format("January"))
+		case 'f':
+			b.WriteString(strconv.Itoa(hour12))
+			if t.Minute() != 0 {
+				b.WriteString(t.Format(":04"))
+			}
+		case 'g':
+			b.WriteString(strconv.Itoa(hour12))
+		case 'G':
+			b.WriteString(strconv.Itoa(t.Hour()))
+		case 'h':
+			fmt.Fprintf(&b, "%02d", hour12)
+		case 'H':
+			b.WriteString(t.Format("15"))
+		case 'i':
+			b.WriteString(t.Format("04"))
+		case 'j':
+			b.WriteString(strconv.Itoa(t.Day()))
+		case 'l':
+			b.WriteString(t.Format("Monday"))
+		case 'L':
+			y := t.Year()
+			b.WriteString(strings.Title(strconv.FormatBool(y%4 == 0 && (y%100 != 0 || y%400 == 0))))
+		case 'm':
+			b.WriteString(t.Format("01"))
+		case 'M':
+			b.WriteString(t.Format("Jan"))
+		case 'n':
+			b.WriteString(strconv.Itoa(int(t.Month())))
+		case 'N':
+			b.WriteString(apMonths[t.Month()-1])
+		case 'o':
+			y, _ := t.ISOWeek()
+			b.WriteString(strconv.Itoa(y))
+		case 'O':
+			b.WriteString(t.Format("-0700"))
+		case 'P':
+			switch {
+			case t.Hour() == 0 && t.Minute() == 0:
+				b.WriteString("midnight")
+			case t.Hour() == 12 && t.Minute() == 0:
+				b.WriteString("noon")
+			default:
+				b.WriteString(DjangoDate(`f a`, t))
+			}
+		case 'r':
+			b.WriteString(t.Format(time.RFC1123Z))
+		case 's':
+			b.WriteString(t.Format("05"))
+		case 'S':
+			b.WriteString(ordinalSuffix(t.Day()))
+		case 't':
+			b.WriteString(strconv.Itoa(time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()))
+		case 'T':
+			b.WriteString(t.Format("MST"))
+		case 'u':
+			fmt.Fprintf(&b, "%06d", t.Nanosecond()/1000)
+		case 'U':
+			b.WriteString(strconv.FormatInt(t.Unix(), 10))
+		case 'w':
+			b.WriteString(strconv.Itoa(int(t.Weekday())))
+		case 'W':
+			_, w := t.ISOWeek()
+			b.WriteString(strconv.Itoa(w))
+		case 'y':
+			b.WriteString(t.Format("06"))
+		case 'Y':
+			b.WriteString(strconv.Itoa(t.Year()))
+		case 'z':
+			b.WriteString(strconv.Itoa(t.YearDay()))
+		case 'Z':
+			_, offset := t.Zone()
+			b.WriteString(strconv.Itoa(offset))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// ordinalSuffix returns the English ordinal suffix of day: "st", "nd",
+// "rd" or "th".
+func ordinalSuffix(day int) string {
+	if day%100 >= 11 && day%100 <= 13 {
+		return "th"
+	}
+	switch day % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	}
+	return "th"
+}
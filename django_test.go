@@ -0,0 +1,24 @@
+package funcmaps
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDjangoDate(t *testing.T) {
+	d := time.Date(2024, 5, 1, 15, 4, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		date interface{}
+		want string
+	}{
+		{d, "May 1, 2024"},
+		{&d, "May 1, 2024"},
+		{nil, ""},
+		{(*time.Time)(nil), ""},
+		{"2024-05-01", ""},
+	} {
+		if got := DjangoDate("N j, Y", tt.date); got != tt.want {
+			t.Errorf("DjangoDate(%#v) = %q, want %q", tt.date, got, tt.want)
+		}
+	}
+}
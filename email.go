@@ -0,0 +1,364 @@
+package funcmaps
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Email returns functions for HTML emails, whose clients often ignore
+// style elements and hidden content conventions of the web.
+func Email() FuncMap {
+	return recordRisks(FuncMap{
+		"inlineCSS":    InlineCSS,
+		"emailSafeImg": EmailSafeImg,
+		"preheader":    Preheader,
+	})
+}
+
+// InlineCSSHook returns a Hook inlining css, and the style elements of the
+// output, into the output, for templates rendering whole emails.
+func InlineCSSHook(css string) Hook {
+	return func(b []byte) ([]byte, error) {
+		s, err := InlineCSS(string(b), css)
+		return []byte(s), err
+	}
+}
+
+// InlineCSS applies the rules of css, and of the style elements of the
+// document, to the style attributes of the matching elements of doc.
+//
+// Selectors are made of type, class, ID and universal selectors, combined
+// with descendant and child combinators, as in "table.main > td.cell".
+// Rules with other selectors, and at-rules such as @media, are left to the
+// style elements. Declarations apply in order of specificity, then source;
+// existing style attributes and !important declarations take precedence.
+func InlineCSS(doc, css interface{}) (template.HTML, error) {
+	docText, err := toText(doc)
+	if err != nil {
+		return "", err
+	}
+	cssText, err := toText(css)
+	if err != nil {
+		return "", err
+	}
+	full := htmlDocRe.MatchString(docText)
+	var roots []*html.Node
+	if full {
+		root, err := html.Parse(strings.NewReader(docText))
+		if err != nil {
+			return "", err
+		}
+		roots = []*html.Node{root}
+	} else {
+		body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+		if roots, err = html.ParseFragment(strings.NewReader(docText), body); err != nil {
+			return "", err
+		}
+	}
+
+	var sheet strings.Builder
+	sheet.WriteString(cssText)
+	for _, root := range roots {
+		walkHTML(root, func(n *html.Node) {
+			if n.Type == html.ElementNode && n.DataAtom == atom.Style && n.FirstChild != nil {
+				sheet.WriteString("\n" + n.FirstChild.Data)
+			}
+		})
+	}
+	rules := parseCSS(sheet.String())
+
+	for _, root := range roots {
+		walkHTML(root, func(n *html.Node) {
+			if n.Type != html.ElementNode || n.DataAtom == atom.Style || n.DataAtom == atom.Head {
+				return
+			}
+			applyCSS(n, rules)
+		})
+	}
+
+	var b bytes.Buffer
+	for _, root := range roots {
+		if err := html.Render(&b, root); err != nil {
+			return "", err
+		}
+	}
+	return template.HTML(b.String()), nil
+}
+
+func walkHTML(n *html.Node, fn func(*html.Node)) {
+	fn(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walkHTML(c, fn)
+	}
+}
+
+// cssRule is a rule with a single supported selector.
+type cssRule struct {
+	sel   []cssCompound // compound selectors, the subject last
+	spec  [3]int        // specificity: IDs, classes, types
+	order int
+	decls []cssDecl
+}
+
+// cssCompound is a compound selector, and the combinator relating it to
+// the compound selector before it: ' ' for descendant, '>' for child.
+type cssCompound struct {
+	comb    byte
+	tag     string
+	id      string
+	classes []string
+}
+
+type cssDecl struct {
+	prop, value string
+	important   bool
+}
+
+var (
+	cssCommentRe  = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	cssCompoundRe = regexp.MustCompile(`^(\*|[a-zA-Z][a-zA-Z0-9-]*)?((?:[#.][a-zA-Z_-][a-zA-Z0-9_-]*)*)$`)
+	cssSubclassRe = regexp.MustCompile(`[#.][^#.]+`)
+	htmlDocRe     = regexp.MustCompile(`(?i)<html[\s>]`)
+)
+
+// parseCSS returns the rules of css with supported selectors, one per
+// selector, skipping at-rules.
+func parseCSS(css string) []cssRule {
+	css = cssCommentRe.ReplaceAllString(css, "")
+	var rules []cssRule
+	for len(css) > 0 {
+		open := strings.IndexByte(css, '{')
+		if open < 0 {
+			break
+		}
+		prelude := strings.TrimSpace(css[:open])
+		// find the matching brace, skipping nested blocks of at-rules
+		depth, end := 0, -1
+		for i := open; i < len(css) && end < 0; i++ {
+			switch css[i] {
+			case '{':
+				depth++
+			case '}':
+				if depth--; depth == 0 {
+					end = i
+				}
+			}
+		}
+		if end < 0 {
+			break
+		}
+		body := css[open+1 : end]
+		css = css[end+1:]
+		if strings.HasPrefix(prelude, "@") {
+			continue
+		}
+		decls := parseDecls(body)
+		for _, sel := range strings.Split(prelude, ",") {
+			if r, ok := parseSelector(strings.TrimSpace(sel)); ok {
+				r.order, r.decls = len(rules), decls
+				rules = append(rules, r)
+			}
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		a, b := rules[i].spec, rules[j].spec
+		if a != b {
+			return a[0] < b[0] || a[0] == b[0] && (a[1] < b[1] || a[1] == b[1] && a[2] < b[2])
+		}
+		return rules[i].order < rules[j].order
+	})
+	return rules
+}
+
+func parseDecls(body string) []cssDecl {
+	var decls []cssDecl
+	for _, d := range strings.Split(body, ";") {
+		colon := strings.IndexByte(d, ':')
+		if colon < 0 {
+			continue
+		}
+		prop := strings.ToLower(strings.TrimSpace(d[:colon]))
+		value := strings.TrimSpace(d[colon+1:])
+		important := false
+		if i := strings.Index(strings.ToLower(value), "!important"); i >= 0 {
+			value, important = strings.TrimSpace(value[:i]), true
+		}
+		if prop != "" && value != "" {
+			decls = append(decls, cssDecl{prop, value, important})
+		}
+	}
+	return decls
+}
+
+func parseSelector(sel string) (cssRule, bool) {
+	var r cssRule
+	sel = strings.ReplaceAll(sel, ">", " > ")
+	comb := byte(' ')
+	for _, part := range strings.Fields(sel) {
+		if part == ">" {
+			comb = '>'
+			continue
+		}
+		m := cssCompoundRe.FindStringSubmatch(part)
+		if m == nil || part == "" {
+			return cssRule{}, false
+		}
+		c := cssCompound{comb: comb, tag: strings.ToLower(m[1])}
+		if c.tag == "*" {
+			c.tag = ""
+		} else if c.tag != "" {
+			r.spec[2]++
+		}
+		for _, sub := range cssSubclassRe.FindAllString(m[2], -1) {
+			if sub[0] == '#' {
+				c.id = sub[1:]
+				r.spec[0]++
+			} else {
+				c.classes = append(c.classes, sub[1:])
+				r.spec[1]++
+			}
+		}
+		r.sel = append(r.sel, c)
+		comb = ' '
+	}
+	return r, len(r.sel) > 0
+}
+
+func (c cssCompound) match(n *html.Node) bool {
+	if n == nil || n.Type != html.ElementNode {
+		return false
+	}
+	if c.tag != "" && n.Data != c.tag {
+		return false
+	}
+	id, class := "", ""
+	for _, a := range n.Attr {
+		switch a.Key {
+		case "id":
+			id = a.Val
+		case "class":
+			class = a.Val
+		}
+	}
+	if c.id != "" && id != c.id {
+		return false
+	}
+	classes := strings.Fields(class)
+	for _, want := range c.classes {
+		found := false
+		for _, have := range classes {
+			if have == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether the selector of r matches n.
+func (r cssRule) matches(n *html.Node) bool {
+	return matchSelector(r.sel, n)
+}
+
+func matchSelector(sel []cssCompound, n *html.Node) bool {
+	last := sel[len(sel)-1]
+	if !last.match(n) {
+		return false
+	}
+	if len(sel) == 1 {
+		return true
+	}
+	rest := sel[:len(sel)-1]
+	if last.comb == '>' {
+		return matchSelector(rest, n.Parent)
+	}
+	for p := n.Parent; p != nil; p = p.Parent {
+		if matchSelector(rest, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyCSS merges the declarations of the rules matching n into its style
+// attribute.
+func applyCSS(n *html.Node, rules []cssRule) {
+	var props []string
+	values := map[string]string{}
+	set := func(prop, value string) {
+		if _, ok := values[prop]; !ok {
+			props = append(props, prop)
+		}
+		values[prop] = value
+	}
+	var important []cssDecl
+	for _, r := range rules {
+		if !r.matches(n) {
+			continue
+		}
+		for _, d := range r.decls {
+			if d.important {
+				important = append(important, d)
+			} else {
+				set(d.prop, d.value)
+			}
+		}
+	}
+	if len(props) == 0 && len(important) == 0 {
+		return
+	}
+	styleIdx := -1
+	for i, a := range n.Attr {
+		if a.Key == "style" {
+			styleIdx = i
+			for _, d := range parseDecls(a.Val) {
+				set(d.prop, d.value)
+			}
+		}
+	}
+	for _, d := range important {
+		set(d.prop, d.value)
+	}
+	decls := make([]string, len(props))
+	for i, p := range props {
+		decls[i] = p + ": " + values[p]
+	}
+	style := strings.Join(decls, "; ")
+	if styleIdx >= 0 {
+		n.Attr[styleIdx].Val = style
+	} else {
+		n.Attr = append(n.Attr, html.Attribute{Key: "style", Val: style})
+	}
+}
+
+// EmailSafeImg returns an img element for email clients: with explicit
+// width, no border or outline, and displayed as a block so that no gaps
+// appear below it. src must be an http, https, cid or image data URL.
+func EmailSafeImg(src, alt string, width int) (template.HTML, error) {
+	lower := strings.ToLower(strings.TrimSpace(src))
+	if !(strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://") ||
+		strings.HasPrefix(lower, "cid:") || strings.HasPrefix(lower, "data:image/")) {
+		return "", fmt.Errorf("emailSafeImg: unsupported image URL %q", src)
+	}
+	return template.HTML(fmt.Sprintf(
+		`<img src="%s" alt="%s" width="%d" border="0" style="display: block; border: 0; outline: none; text-decoration: none; height: auto; max-width: 100%%">`,
+		html.EscapeString(src), html.EscapeString(alt), width)), nil
+}
+
+// Preheader returns s as the preheader of an email: the preview text shown
+// by inboxes after the subject, hidden in the message itself.
+func Preheader(s string) template.HTML {
+	return template.HTML(`<div style="display: none; max-height: 0; max-width: 0; overflow: hidden; opacity: 0; ` +
+		`font-size: 1px; line-height: 1px; color: transparent; mso-hide: all">` + html.EscapeString(s) + `</div>`)
+}
@@ -0,0 +1,156 @@
+package funcmaps
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// Encoding returns functions encoding and decoding text as base64 and hex,
+// and inspecting binary data. They accept strings and byte slices alike.
+//
+//	stringToHexDump "hi"  => "68 69"
+//	prettyHexDump .Body   => the layout of hexdump -C, with offsets and text
+//	chunkBytes 16 .Body   => the bytes in slices of 16
+func Encoding() FuncMap {
+	return FuncMap{
+		"bytesToString":   bytesToString,
+		"stringToHexDump": hexBytes,
+		"prettyHexDump":   hexDump,
+		"chunkBytes":      ChunkBytes,
+		"base64Encode":    base64Encoder(base64.StdEncoding),
+		"base64Decode":    base64Decoder(base64.StdEncoding),
+		"base64URLEncode": base64Encoder(base64.RawURLEncoding),
+		"base64URLDecode": base64Decoder(base64.RawURLEncoding),
+		"hexEncode":       hexEncode,
+		"hexDecode":       hexDecode,
+	}
+}
+
+// Sanitizers returns functions removing unsafe markup from user supplied
+// text. They accept strings and byte slices alike.
+func Sanitizers() FuncMap {
+	return FuncMap{
+		"sanitize": func(v interface{}) (template.HTML, error) {
+			s, err := toText(v)
+			return template.HTML(Sanitize(s)), err
+		},
+		"stripTags":         textFunc(StripTags),
+		"stripTagsSentence": textFunc(StripTagsSentence),
+	}
+}
+
+func base64Encoder(enc *base64.Encoding) func(interface{}) (string, error) {
+	return func(v interface{}) (string, error) {
+		if b, ok := v.([]byte); ok {
+			return enc.EncodeToString(b), nil
+		}
+		s, err := toText(v)
+		if err != nil {
+			return "", err
+		}
+		return enc.EncodeToString([]byte(s)), nil
+	}
+}
+
+func base64Decoder(enc *base64.Encoding) func(interface{}) (string, error) {
+	return func(v interface{}) (string, error) {
+		if b, ok := v.([]byte); ok {
+			dst := make([]byte, enc.DecodedLen(len(b)))
+			n, err := enc.Decode(dst, b)
+			return string(dst[:n]), err
+		}
+		s, err := toText(v)
+		if err != nil {
+			return "", err
+		}
+		b, err := enc.DecodeString(s)
+		return string(b), err
+	}
+}
+
+func hexEncode(v interface{}) (string, error) {
+	if b, ok := v.([]byte); ok {
+		return hex.EncodeToString(b), nil
+	}
+	s, err := toText(v)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString([]byte(s)), nil
+}
+
+func hexDecode(v interface{}) (string, error) {
+	if b, ok := v.([]byte); ok {
+		dst := make([]byte, hex.DecodedLen(len(b)))
+		n, err := hex.Decode(dst, b)
+		return string(dst[:n]), err
+	}
+	s, err := toText(v)
+	if err != nil {
+		return "", err
+	}
+	b, err := hex.DecodeString(s)
+	return string(b), err
+}
+
+// bytesOf returns v as bytes, as toText accepts it.
+func bytesOf(v interface{}) ([]byte, error) {
+	if b, ok := v.([]byte); ok {
+		return b, nil
+	}
+	s, err := toText(v)
+	return []byte(s), err
+}
+
+func bytesToString(v interface{}) (string, error) {
+	return toText(v)
+}
+
+// hexBytes returns the bytes of v in hex, separated by spaces.
+func hexBytes(v interface{}) (string, error) {
+	b, err := bytesOf(v)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for i, c := range b {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(hex.EncodeToString([]byte{c}))
+	}
+	return sb.String(), nil
+}
+
+// hexDump returns the bytes of v in the canonical layout of hexdump -C.
+func hexDump(v interface{}) (string, error) {
+	b, err := bytesOf(v)
+	if err != nil {
+		return "", err
+	}
+	return hex.Dump(b), nil
+}
+
+// ChunkBytes splits the bytes of v, a string or byte slice, into slices of
+// n bytes, the last one possibly shorter.
+func ChunkBytes(n int, v interface{}) ([][]byte, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("chunkBytes: invalid size %d", n)
+	}
+	b, err := bytesOf(v)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([][]byte, 0, (len(b)+n-1)/n)
+	for len(b) > n {
+		rs = append(rs, b[:n:n])
+		b = b[n:]
+	}
+	if len(b) > 0 {
+		rs = append(rs, b)
+	}
+	return rs, nil
+}
@@ -0,0 +1,189 @@
+package funcmaps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RenderError is an error executing a template, with the location of the
+// failing action and the function call that failed, if any.
+//
+// Argument values are formatted with fmt, so Secret values show as Redacted.
+type RenderError struct {
+	Template string   // name of the template containing the failing action
+	Line     int      // line of the failing action, or zero when unknown
+	Column   int      // column of the failing action, or zero when unknown
+	Action   string   // the failing action, eg. ".User.Name"
+	Func     string   // name of the function that failed, if any
+	Args     []string // arguments of the failed function call
+	Message  string   // the error without the location
+	Err      error    // the error returned by the template package
+}
+
+// Error returns the location, failed call and message of the error.
+func (e *RenderError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "template %q", e.Template)
+	if e.Line > 0 {
+		fmt.Fprintf(&b, " line %d", e.Line)
+		if e.Column > 0 {
+			fmt.Fprintf(&b, " col %d", e.Column)
+		}
+	}
+	if e.Func != "" {
+		fmt.Fprintf(&b, ": calling %s(%s)", e.Func, strings.Join(e.Args, ", "))
+	} else if e.Action != "" {
+		fmt.Fprintf(&b, ": at <%s>", e.Action)
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// Unwrap returns the error returned by the template package.
+func (e *RenderError) Unwrap() error { return e.Err }
+
+// execErrorRe matches the location text/template prefixes execution errors
+// with. Nested executions, such as partials, add one prefix each.
+var execErrorRe = regexp.MustCompile(`template: (.+?):(\d+):(\d+): executing "(?:[^"\\]|\\.)*" at <(.*?)>: `)
+
+var errorCallingRe = regexp.MustCompile(`^error calling (\w+): `)
+
+// failedCall is a function call that returned an error or panicked.
+type failedCall struct {
+	name string
+	args []string
+}
+
+// newRenderError returns err as a *RenderError, using the innermost
+// location found in its message. call is the first failed function call
+// of the execution, or nil.
+func newRenderError(name string, err error, call *failedCall) error {
+	var re *RenderError
+	if err == nil || errors.As(err, &re) {
+		return err
+	}
+	re = &RenderError{Template: name, Message: err.Error(), Err: err}
+	var herr *template.Error
+	if errors.As(err, &herr) {
+		re.Template, re.Line, re.Message = herr.Name, herr.Line, herr.Description
+	}
+	msg := err.Error()
+	if loc := execErrorRe.FindAllStringSubmatchIndex(msg, -1); len(loc) > 0 {
+		m := loc[len(loc)-1]
+		re.Template = msg[m[2]:m[3]]
+		re.Line, _ = strconv.Atoi(msg[m[4]:m[5]])
+		re.Column, _ = strconv.Atoi(msg[m[6]:m[7]])
+		re.Action = msg[m[8]:m[9]]
+		re.Message = msg[m[1]:]
+		if sub := errorCallingRe.FindStringSubmatch(re.Message); sub != nil {
+			re.Func, re.Message = sub[1], re.Message[len(sub[0]):]
+		}
+	}
+	if call != nil && (re.Func == "" || re.Func == call.name) {
+		re.Func, re.Args = call.name, call.args
+	}
+	return re
+}
+
+// formatArg formats a function argument for a RenderError.
+func formatArg(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return stringifyValue(v)
+}
+
+// callRecorder records the first failed call of the functions it wraps,
+// and aborts calls once the context of the execution is done.
+// It is used by a single execution at a time.
+type callRecorder struct {
+	failed *failedCall
+	ctx    context.Context // nil if the execution has no context
+}
+
+func (r *callRecorder) reset(ctx context.Context) { r.failed, r.ctx = nil, ctx }
+
+// wrap returns fm with every function recording its failed calls.
+func (r *callRecorder) wrap(fm FuncMap) FuncMap {
+	return wrapFuncs(fm, func(name string, args []reflect.Value, next func([]reflect.Value) []reflect.Value) []reflect.Value {
+		if r.ctx != nil && r.ctx.Err() != nil {
+			panic(r.ctx.Err()) // reported by the template package as the call's error
+		}
+		ok := false
+		defer func() {
+			if !ok && r.failed == nil {
+				typ := reflect.ValueOf(fm[name]).Type()
+				vals := callArgs(typ, args)
+				call := &failedCall{name: name, args: make([]string, len(vals))}
+				for i, v := range vals {
+					call.args[i] = formatArg(v)
+				}
+				r.failed = call
+			}
+		}()
+		out := next(args)
+		ok = callError(out) == nil
+		return out
+	})
+}
+
+var renderErrorPage = template.Must(template.New("").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Template error</title>
+<style>
+body { font: 15px/1.5 sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.4em; color: #b00; }
+pre { background: #f4f4f4; padding: 1em; overflow-x: auto; }
+th { text-align: left; padding-right: 1em; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>Template error</h1>
+<pre>{{.Message}}</pre>
+<table>
+<tr><th>Template</th><td><code>{{.Template}}</code></td></tr>
+{{- if .Line}}
+<tr><th>Location</th><td>line {{.Line}}{{if .Column}}, column {{.Column}}{{end}}</td></tr>
+{{- end}}
+{{- if .Action}}
+<tr><th>Action</th><td><code>{{"{{"}}{{.Action}}{{"}}"}}</code></td></tr>
+{{- end}}
+{{- if .Func}}
+<tr><th>Function</th><td><code>{{.Func}}</code></td></tr>
+<tr><th>Arguments</th><td>{{range $i, $a := .Args}}<code>{{$a}}</code><br>{{else}}none{{end}}</td></tr>
+{{- end}}
+</table>
+<pre>{{.Err}}</pre>
+</body>
+</html>
+`))
+
+// WriteHTML writes a page describing the error, for development.
+func (e *RenderError) WriteHTML(w io.Writer) error {
+	return renderErrorPage.Execute(w, e)
+}
+
+// ServeError responds to a request that failed with err. In development
+// mode, render errors are shown with their details; otherwise a generic
+// 500 Internal Server Error is written, and err should be logged instead.
+func (t *Templates) ServeError(w http.ResponseWriter, err error) {
+	var re *RenderError
+	if t.dev > 0 && errors.As(err, &re) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		re.WriteHTML(w)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
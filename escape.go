@@ -0,0 +1,61 @@
+package funcmaps
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+)
+
+// Escapes returns functions escaping text for HTML, JavaScript, JSON and
+// CSS, for text/template output, which is not escaped contextually as
+// html/template output is. They accept strings and byte slices alike.
+//
+//	htmlEscape `<a href="x">`    => "&lt;a href=&#34;x&#34;&gt;"
+//	htmlUnescape "&lt;b&gt;"      => "<b>"
+//	jsEscape `it's "x"`           => `it\'s \"x\"`
+//	jsonEscape "a\n</script>"     => `a\n\u003c/script\u003e`
+//	cssEscape "1st item"          => `\31 st\ item`
+func Escapes() FuncMap {
+	return FuncMap{
+		"htmlEscape":   textFunc(template.HTMLEscapeString),
+		"htmlUnescape": textFunc(html.UnescapeString),
+		"jsEscape":     textFunc(template.JSEscapeString),
+		"jsonEscape":   textFunc(JSONEscape),
+		"cssEscape":    textFunc(CSSEscape),
+	}
+}
+
+// JSONEscape escapes s for use inside a JSON string, without the quotes.
+// "<", ">" and "&" are escaped too, so that the output is safe in HTML
+// script elements.
+func JSONEscape(s string) string {
+	b, _ := json.Marshal(s) // strings always marshal
+	return string(b[1 : len(b)-1])
+}
+
+// CSSEscape escapes s for use as a CSS identifier, such as a class name in
+// a selector, as the CSS.escape function of browsers does.
+func CSSEscape(s string) string {
+	var b strings.Builder
+	rs := []rune(s)
+	for i, r := range rs {
+		switch {
+		case r == 0:
+			b.WriteRune('�')
+		case r >= 0x01 && r <= 0x1f || r == 0x7f,
+			i == 0 && r >= '0' && r <= '9',
+			i == 1 && r >= '0' && r <= '9' && rs[0] == '-':
+			fmt.Fprintf(&b, "\\%x ", r)
+		case i == 0 && r == '-' && len(rs) == 1:
+			b.WriteString(`\-`)
+		case r >= 0x80 || r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
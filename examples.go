@@ -0,0 +1,222 @@
+package funcmaps
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// Example is a runnable example of a template function: executing Template
+// with Data gives Output.
+type Example struct {
+	Func     string
+	Template string
+	Data     interface{}
+	Output   string
+}
+
+// ExampleFuncs returns the functions used by the examples of
+// ExampleCatalog.
+func ExampleFuncs() FuncMap {
+	return Combined(Default(), Collections(), Colors(), Names(), Privacy(),
+		Unicode(), Codegen(), Encoding(), Escapes(), Shell(), SQL(), K8s(), Semver(), Money(), Statistics(), Fuzzy())
+}
+
+// nonExampleFuncs are the functions of ExampleFuncs without an example.
+var nonExampleFuncs = []string{"NOW", "now", "env", "uuid"}
+
+// ExampleCatalog returns examples of the functions of the package, which
+// document them and check that their behavior does not change unnoticed;
+// see VerifyExamples. It has an example of every function of ExampleFuncs
+// except now, NOW, env and uuid, whose results depend on the clock, the
+// environment or random numbers.
+func ExampleCatalog() []Example {
+	return []Example{
+		{Func: "toLower", Template: `{{toLower .}}`, Data: "Hello", Output: "hello"},
+		{Func: "toUpper", Template: `{{toUpper .}}`, Data: "Hello", Output: "HELLO"},
+		{Func: "trim", Template: `{{trim "-" .}}`, Data: "--a-b--", Output: "a-b"},
+		{Func: "trim_prefix", Template: `{{. | trim_prefix "www."}}`, Data: "www.example.com", Output: "example.com"},
+		{Func: "has_suffix", Template: `{{has_suffix ".go" .}}`, Data: "main.go", Output: "true"},
+		{Func: "replace_all", Template: `{{replace_all "-" "_" .}}`, Data: "a-b-c", Output: "a_b_c"},
+		{Func: "split", Template: `{{range split "," .}}[{{.}}]{{end}}`, Data: "a,b", Output: "[a][b]"},
+		{Func: "wc", Template: `{{wc .}}`, Data: "the quick brown fox", Output: "4"},
+		{Func: "json", Template: `{{json .}}`, Data: map[string]int{"a": 1}, Output: `{"a":1}`},
+		{Func: "indent", Template: `{{indent . "> "}}`, Data: "a\nb", Output: "> a\n> b"},
+		{Func: "unexport", Template: `{{unexport .}}`, Data: "UserName", Output: "userName"},
+		{Func: "rev", Template: `{{rev .}}`, Data: "abc", Output: "cba"},
+		{Func: "yesno", Template: `{{yesno . "on" "off"}}`, Data: true, Output: "on"},
+		{Func: "coalesce", Template: `{{coalesce "" 0 "x"}}`, Output: "x"},
+		{Func: "blank", Template: `{{blank .}}`, Data: "  ", Output: "true"},
+		{Func: "date", Template: `{{date "%Y-%m-%d" "UTC" .}}`, Data: 86400, Output: "1970-01-02"},
+		{Func: "date_locale", Template: `{{date_locale "January 2006" "UTC" "fr" .}}`, Data: 0, Output: "janvier 1970"},
+		{Func: "has", Template: `{{has . 2}}`, Data: []int{1, 2, 3}, Output: "true"},
+		{Func: "file_size", Template: `{{file_size .}}`, Data: 1536, Output: "1.5 KB"},
+		{Func: "fileSize", Template: `{{fileSize . "si" 2}}`, Data: 1536000, Output: "1.54 MB"},
+		{Func: "parseFileSize", Template: `{{parseFileSize .}}`, Data: "1.5GiB", Output: "1610612736"},
+		{Func: "repeat", Template: `{{repeat 3 .}}`, Data: "ab", Output: "ababab"},
+		{Func: "seq", Template: `{{seq 10 0 -5}}`, Output: "[10 5 0]"},
+		{Func: "until", Template: `{{range until .}}*{{end}}`, Data: 3, Output: "***"},
+		{Func: "join2", Template: `{{join2 ", " .}}`, Data: []string{"a", "b"}, Output: "a, b"},
+		{Func: "map", Template: `{{with map "a" 1 "b" 2}}{{.b}}{{end}}`, Output: "2"},
+		{Func: "union", Template: `{{union .A .B}}`, Data: map[string][]int{"A": {1, 2}, "B": {2, 3}}, Output: "[1 2 3]"},
+		{Func: "intersection", Template: `{{intersection .A .B}}`, Data: map[string][]int{"A": {1, 2}, "B": {2, 3}}, Output: "[2]"},
+		{Func: "difference", Template: `{{difference .A .B}}`, Data: map[string][]int{"A": {1, 2}, "B": {2, 3}}, Output: "[1]"},
+		{Func: "sortNatural", Template: `{{sortNatural .}}`, Data: []string{"v10", "v2", "v1"}, Output: "[v1 v2 v10]"},
+		{Func: "sortLocale", Template: `{{sortLocale "sv" .}}`, Data: []string{"Ärger", "Zebra", "Arm"}, Output: "[Arm Zebra Ärger]"},
+		{Func: "symmetricDifference", Template: `{{symmetricDifference .A .B}}`, Data: map[string][]int{"A": {1, 2}, "B": {2, 3}}, Output: "[1 3]"},
+		{Func: "columns", Template: `{{columns 3 .}}`, Data: []int{1, 2, 3, 4, 5, 6, 7}, Output: "[[1 4 7] [2 5] [3 6]]"},
+		{Func: "transpose", Template: `{{transpose .}}`, Data: [][]int{{1, 2}, {3, 4}}, Output: "[[1 3] [2 4]]"},
+		{Func: "flatten", Template: `{{flatten .}}`, Data: [][]int{{1}, {2, 3}}, Output: "[1 2 3]"},
+		{Func: "zip", Template: `{{zip .A .B}}`, Data: map[string][]string{"A": {"a", "b", "c"}, "B": {"1", "2"}}, Output: "[[a 1] [b 2]]"},
+		{Func: "dictFromLists", Template: `{{with dictFromLists .A .B}}{{.b}}{{end}}`, Data: map[string][]string{"A": {"a", "b"}, "B": {"1", "2"}}, Output: "2"},
+		{Func: "lighten", Template: `{{lighten 20 .}}`, Data: "#336699", Output: "#6699cc"},
+		{Func: "contrastColor", Template: `{{contrastColor .}}`, Data: "#ffff00", Output: "#000000"},
+		{Func: "initials", Template: `{{initials .}}`, Data: "Ada King Lovelace", Output: "AL"},
+		{Func: "possessive", Template: `{{possessive .}}`, Data: "James", Output: "James'"},
+		{Func: "formatName", Template: `{{formatName "Ada" "Lovelace" "Last, F."}}`, Output: "Lovelace, A."},
+		{Func: "mask", Template: `{{mask 4 .}}`, Data: "12345678", Output: "****5678"},
+		{Func: "maskEmail", Template: `{{maskEmail .}}`, Data: "ada@example.com", Output: "a**@example.com"},
+		{Func: "removeDiacritics", Template: `{{removeDiacritics .}}`, Data: "Crème brûlée", Output: "Creme brulee"},
+		{Func: "graphemeLen", Template: `{{graphemeLen .}}`, Data: "👩\u200d💻!", Output: "2"},
+		{Func: "graphemeRev", Template: `{{graphemeRev .}}`, Data: "👍🏽ok", Output: "ko👍🏽"},
+		{Func: "emojify", Template: `{{emojify .}}`, Data: "ship it :+1:", Output: "ship it 👍"},
+		{Func: "export", Template: `{{export .}}`, Data: "userName", Output: "UserName"},
+		{Func: "receiverName", Template: `{{receiverName .}}`, Data: "*UserStore", Output: "u"},
+		{Func: "zeroValue", Template: `{{zeroValue .}}`, Data: "*T", Output: "nil"},
+		{Func: "importAlias", Template: `{{importAlias .}}`, Data: "gopkg.in/yaml.v2", Output: "yaml"},
+		{Func: "base64Encode", Template: `{{base64Encode .}}`, Data: "hello", Output: "aGVsbG8="},
+		{Func: "hexEncode", Template: `{{hexEncode .}}`, Data: []byte("hi"), Output: "6869"},
+		{Func: "stringToHexDump", Template: `{{stringToHexDump .}}`, Data: []byte("hi"), Output: "68 69"},
+		{Func: "htmlEscape", Template: `{{htmlEscape .}}`, Data: "<b>", Output: "&lt;b&gt;"},
+		{Func: "cssEscape", Template: `{{cssEscape .}}`, Data: "1st item", Output: `\31 st\ item`},
+		{Func: "shquote", Template: `{{shquote .}}`, Data: "it's", Output: `'it'"'"'s'`},
+		{Func: "shjoin", Template: `{{shjoin .}}`, Data: []string{"echo", "a b"}, Output: "echo 'a b'"},
+		{Func: "sqlIdent", Template: `{{sqlIdent "public" .}}`, Data: "users", Output: `"public"."users"`},
+		{Func: "sqlString", Template: `{{sqlString .}}`, Data: "O'Brien", Output: "'O''Brien'"},
+		{Func: "sqlIn", Template: `{{sqlIn .}}`, Data: []interface{}{1, "a"}, Output: "(1, 'a')"},
+		{Func: "indentYaml", Template: `{{indentYaml 2 .}}`, Data: "a: 1\nb: 2", Output: "  a: 1\n  b: 2"},
+		{Func: "formatQuantity", Template: `{{formatQuantity .}}`, Data: 536870912.0, Output: "512Mi"},
+		{Func: "semverCompare", Template: `{{semverCompare "^1.2" .}}`, Data: "1.9.3", Output: "true"},
+		{Func: "semverSort", Template: `{{semverSort .}}`, Data: []string{"1.10.0", "1.2.0"}, Output: "[1.2.0 1.10.0]"},
+		{Func: "formatMoney", Template: `{{formatMoney (money . "USD")}}`, Data: 123456, Output: "$1,234.56"},
+		{Func: "moneyMul", Template: `{{moneyMul "0.0825" (money "19.99" "USD")}}`, Output: "$1.65"},
+		{Func: "median", Template: `{{median .}}`, Data: []int{35, 10, 15, 20}, Output: "17.5"},
+		{Func: "percentile", Template: `{{percentile 90 .}}`, Data: []float64{1, 2, 3, 4, 5}, Output: "4.6"},
+		{Func: "levenshtein", Template: `{{levenshtein "kitten" .}}`, Data: "sitting", Output: "3"},
+		{Func: "fuzzyMatch", Template: `{{fuzzyMatch "fb" .}}`, Data: "FooBar", Output: "true"},
+		{Func: "toTitle", Template: `{{toTitle .}}`, Data: "hello", Output: "HELLO"},
+		{Func: "title", Template: `{{title .}}`, Data: "hello world", Output: "Hello World"},
+		{Func: "lower", Template: `{{lower .}}`, Data: "Hello", Output: "hello"},
+		{Func: "upper", Template: `{{upper .}}`, Data: "Hello", Output: "HELLO"},
+		{Func: "string", Template: `{{string .}}`, Data: []int{1, 2}, Output: "[1 2]"},
+		{Func: "trimspace", Template: `{{trimspace .}}`, Data: "  a b  ", Output: "a b"},
+		{Func: "trim_left", Template: `{{trim_left "-" .}}`, Data: "--a--", Output: "a--"},
+		{Func: "trim_right", Template: `{{trim_right "-" .}}`, Data: "--a--", Output: "--a"},
+		{Func: "trim_suffix", Template: `{{trim_suffix ".go" .}}`, Data: "main.go", Output: "main"},
+		{Func: "fields", Template: `{{range fields .}}[{{.}}]{{end}}`, Data: " a  b ", Output: "[a][b]"},
+		{Func: "has_prefix", Template: `{{has_prefix "www." .}}`, Data: "www.example.com", Output: "true"},
+		{Func: "replace", Template: `{{replace "a" "o" 1 .}}`, Data: "banana", Output: "bonana"},
+		{Func: "count", Template: `{{count "a" .}}`, Data: "banana", Output: "3"},
+		{Func: "split_n", Template: `{{range split_n "," 2 .}}[{{.}}]{{end}}`, Data: "a,b,c", Output: "[a][b,c]"},
+		{Func: "backtick", Template: `{{backtick .}}`, Data: "ls", Output: "`ls`"},
+		{Func: "backticks", Template: `{{backticks "sh" .}}`, Data: "ls", Output: "```sh\nls\n```"},
+		{Func: "contains", Template: `{{contains . "team"}}`, Data: "teamwork", Output: "true"},
+		{Func: "prettyjson", Template: `{{prettyjson .}}`, Data: map[string]int{"a": 1}, Output: "{\n  \"a\": 1\n}"},
+		{Func: "join", Template: `{{join . ", "}}`, Data: []string{"a", "b"}, Output: "a, b"},
+		{Func: "add", Template: `{{add 1 .}}`, Data: 2, Output: "3"},
+		{Func: "int", Template: `{{int .}}`, Data: "42", Output: "42"},
+		{Func: "is_true", Template: `{{is_true .}}`, Data: "yes", Output: "true"},
+		{Func: "is_empty", Template: `{{is_empty .}}`, Data: "", Output: "true"},
+		{Func: "is_default", Template: `{{is_default "none" .}}`, Data: "", Output: "none"},
+		{Func: "present", Template: `{{present .}}`, Data: "x", Output: "true"},
+		{Func: "anyPresent", Template: `{{anyPresent "" .}}`, Data: "x", Output: "true"},
+		{Func: "ternary", Template: `{{ternary . "on" "off"}}`, Data: true, Output: "on"},
+		{Func: "has_any", Template: `{{has_any . "b" "z"}}`, Data: []string{"a", "b"}, Output: "true"},
+		{Func: "repeat_n", Template: `{{repeat_n 10 3 .}}`, Data: "ab", Output: "ababab"},
+		{Func: "eq_any", Template: `{{eq_any . "a" "b"}}`, Data: "b", Output: "true"},
+		{Func: "deep_eq", Template: `{{deep_eq . .}}`, Data: []int{1}, Output: "true"},
+		{Func: "intersect", Template: `{{intersect . (split "," "b,c")}}`, Data: []string{"a", "b"}, Output: "[b]"},
+		{Func: "frequencies", Template: `{{frequencies .}}`, Data: []string{"a", "b", "a"}, Output: "map[a:2 b:1]"},
+		{Func: "topN", Template: `{{range topN 1 (frequencies .)}}{{.Value}}={{.Count}}{{end}}`, Data: []string{"a", "b", "a"}, Output: "a=2"},
+		{Func: "unzip", Template: `{{unzip .}}`, Data: [][]interface{}{[]interface{}{"a", 1}, []interface{}{"b", 2}}, Output: "[[a b] [1 2]]"},
+		{Func: "paginate", Template: `{{with paginate . 2 2}}{{.Items}} {{.Page}}/{{.TotalPages}}{{end}}`, Data: []int{1, 2, 3, 4, 5}, Output: "[3 4] 2/3"},
+		{Func: "darken", Template: `{{darken 10 .}}`, Data: "#3366cc", Output: "#2952a3"},
+		{Func: "mix", Template: `{{mix 50 "#ffffff" .}}`, Data: "#000000", Output: "#808080"},
+		{Func: "hexToRGB", Template: `{{with hexToRGB .}}{{.R}},{{.G}},{{.B}}{{end}}`, Data: "#3366cc", Output: "51,102,204"},
+		{Func: "rgbToHex", Template: `{{rgbToHex 51 102 .}}`, Data: 204, Output: "#3366cc"},
+		{Func: "initialsN", Template: `{{initialsN 3 .}}`, Data: "Ada King Lovelace", Output: "AKL"},
+		{Func: "initialsLocale", Template: `{{initialsLocale "tr" .}}`, Data: "ilker iğdır", Output: "İİ"},
+		{Func: "maskCard", Template: `{{maskCard .}}`, Data: "4111 1111 1111 1234", Output: "**** **** **** 1234"},
+		{Func: "redact", Template: `{{redact "[0-9]+" .}}`, Data: "order 1234", Output: "order [REDACTED]"},
+		{Func: "deEmoji", Template: `{{deEmoji .}}`, Data: "hi 👋", Output: "hi "},
+		{Func: "firstGrapheme", Template: `{{firstGrapheme .}}`, Data: "éa", Output: "é"},
+		{Func: "graphemeTrunc", Template: `{{graphemeTrunc 2 .}}`, Data: "héllo", Output: "hé"},
+		{Func: "runeLen", Template: `{{runeLen .}}`, Data: "héllo", Output: "5"},
+		{Func: "normalizeNFC", Template: `{{len (normalizeNFC .)}}`, Data: "é", Output: "2"},
+		{Func: "normalizeNFD", Template: `{{len (normalizeNFD .)}}`, Data: "é", Output: "3"},
+		{Func: "goComment", Template: `{{goComment .}}`, Data: "Hello world.", Output: "// Hello world."},
+		{Func: "goType", Template: `{{goType .}}`, Data: []string{"a"}, Output: "[]string"},
+		{Func: "base64URLEncode", Template: `{{base64URLEncode .}}`, Data: "a?b", Output: "YT9i"},
+		{Func: "base64URLDecode", Template: `{{base64URLDecode .}}`, Data: "YT9i", Output: "a?b"},
+		{Func: "base64Decode", Template: `{{base64Decode .}}`, Data: "aGk=", Output: "hi"},
+		{Func: "hexDecode", Template: `{{hexDecode .}}`, Data: "6869", Output: "hi"},
+		{Func: "bytesToString", Template: `{{bytesToString .}}`, Data: []byte("hi"), Output: "hi"},
+		{Func: "chunkBytes", Template: `{{range chunkBytes 2 .}}[{{printf "%s" .}}]{{end}}`, Data: "abcde", Output: "[ab][cd][e]"},
+		{Func: "prettyHexDump", Template: `{{prettyHexDump .}}`, Data: "hi", Output: "00000000  68 69                                             |hi|\n"},
+		{Func: "htmlUnescape", Template: `{{htmlUnescape .}}`, Data: "a &amp; b", Output: "a & b"},
+		{Func: "jsEscape", Template: `{{jsEscape .}}`, Data: "a'b", Output: "a\\'b"},
+		{Func: "jsonEscape", Template: `{{jsonEscape .}}`, Data: "a\"b", Output: "a\\\"b"},
+		{Func: "envExport", Template: `{{envExport .}}`, Data: map[string]string{"A": "1 2"}, Output: "export A='1 2'"},
+		{Func: "b64secret", Template: `{{b64secret .}}`, Data: map[string]string{"a": "hi"}, Output: "a: aGk="},
+		{Func: "quoteAll", Template: `{{quoteAll .}}`, Data: []string{"a", "b"}, Output: "[\"a\" \"b\"]"},
+		{Func: "parseQuantity", Template: `{{printf "%.0f" (parseQuantity .)}}`, Data: "512Mi", Output: "536870912"},
+		{Func: "toYamlPretty", Template: `{{toYamlPretty .}}`, Data: map[string]interface{}{"a": []int{1, 2}}, Output: "a:\n- 1\n- 2"},
+		{Func: "deepMerge", Template: `{{deepMerge .A .B}}`, Data: map[string]interface{}{"A": map[string]interface{}{"a": 1, "b": 1}, "B": map[string]interface{}{"b": 2}}, Output: "map[a:1 b:2]"},
+		{Func: "deepMergeWithPolicy", Template: `{{deepMergeWithPolicy "lists=append" . .}}`, Data: map[string]interface{}{"a": []interface{}{1}}, Output: "map[a:[1 1]]"},
+		{Func: "semverMajor", Template: `{{semverMajor .}}`, Data: "v1.2.3", Output: "1"},
+		{Func: "semverMinor", Template: `{{semverMinor .}}`, Data: "v1.2.3", Output: "2"},
+		{Func: "semverPatch", Template: `{{semverPatch .}}`, Data: "v1.2.3", Output: "3"},
+		{Func: "money", Template: `{{money . "USD"}}`, Data: 1999, Output: "$19.99"},
+		{Func: "moneyAdd", Template: `{{moneyAdd (money . "USD") (money 1 "USD")}}`, Data: 1999, Output: "$20.00"},
+		{Func: "sum", Template: `{{sum .}}`, Data: []int{10, 15, 35}, Output: "60"},
+		{Func: "avg", Template: `{{avg .}}`, Data: []int{10, 15, 35}, Output: "20"},
+		{Func: "mean", Template: `{{mean .}}`, Data: []int{10, 15, 35}, Output: "20"},
+		{Func: "minOf", Template: `{{minOf .}}`, Data: []int{10, 15, 35}, Output: "10"},
+		{Func: "maxOf", Template: `{{maxOf .}}`, Data: []int{10, 15, 35}, Output: "35"},
+		{Func: "stddev", Template: `{{stddev .}}`, Data: []int{2, 4, 4, 4, 5, 5, 7, 9}, Output: "2"},
+		{Func: "sparkline", Template: `{{sparkline .}}`, Data: []int{1, 3, 5, 8, 2}, Output: "▁▃▅█▂"},
+		{Func: "histogram", Template: `{{histogram 2 .}}`, Data: []int{1, 2, 3, 4}, Output: "1–2.5 │██████████████████████████████ 2\n2.5–4 │██████████████████████████████ 2"},
+		{Func: "similarity", Template: `{{printf "%.2f" (similarity "kitten" .)}}`, Data: "sitting", Output: "0.57"},
+		{Func: "newScratch", Template: `{{$s := newScratch}}{{$s.Set "a" .}}{{$s.Get "a"}}`, Data: 1, Output: "1"},
+	}
+}
+
+// Run executes the example with the functions in fm, returning its output.
+func (e Example) Run(fm FuncMap) (string, error) {
+	t, err := template.New(e.Func).Funcs(template.FuncMap(fm)).Parse(e.Template)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, e.Data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// VerifyExamples runs every example with the functions in fm, such as
+// those of ExampleFuncs, returning an error for each example that failed
+// or whose output differs.
+func VerifyExamples(fm FuncMap, examples []Example) []error {
+	var errs []error
+	for _, e := range examples {
+		out, err := e.Run(fm)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("example %s: %w", e.Func, err))
+		case out != e.Output:
+			errs = append(errs, fmt.Errorf("example %s: got %q, want %q", e.Func, out, e.Output))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,24 @@
+package funcmaps
+
+import "testing"
+
+func TestExamples(t *testing.T) {
+	for _, err := range VerifyExamples(ExampleFuncs(), ExampleCatalog()) {
+		t.Error(err)
+	}
+}
+
+func TestExamplesCoverFuncs(t *testing.T) {
+	covered := map[string]bool{}
+	for _, name := range nonExampleFuncs {
+		covered[name] = true
+	}
+	for _, e := range ExampleCatalog() {
+		covered[e.Func] = true
+	}
+	for name := range ExampleFuncs() {
+		if !covered[name] {
+			t.Errorf("no example of %s", name)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package funcmaps
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	iecUnits = []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	siUnits  = []string{"kB", "MB", "GB", "TB", "PB", "EB"}
+)
+
+// FileSize returns the number of bytes v as a size with units, such as
+// "1.5 KiB". The options are:
+//
+//	"iec"  units of 1024 bytes: KiB, MiB, GiB and so on (default)
+//	"si"   units of 1000 bytes: kB, MB, GB and so on
+//	n      n digits after the decimal point (default 1)
+//
+// A fraction of only zeros is left out, as in "2 KiB". A nil value, for
+// missing data, gives "". Other values that are not finite numbers, or
+// strings of them, are errors.
+func FileSize(v interface{}, opts ...interface{}) (string, error) {
+	base, units, prec := 1024.0, iecUnits, 1
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case string:
+			switch strings.ToLower(opt) {
+			case "iec":
+				base, units = 1024, iecUnits
+			case "si":
+				base, units = 1000, siUnits
+			default:
+				return "", fmt.Errorf("fileSize: invalid option %q", opt)
+			}
+		case int:
+			if opt < 0 || opt > 10 {
+				return "", fmt.Errorf("fileSize: invalid precision %d", opt)
+			}
+			prec = opt
+		default:
+			return "", fmt.Errorf("fileSize: invalid option %v", opt)
+		}
+	}
+	if v == nil {
+		return "", nil
+	}
+	size, err := fileSizeValue(v)
+	if err != nil {
+		return "", err
+	}
+	if math.Abs(size) < base {
+		if size == 1 {
+			return "1 byte", nil
+		}
+		return strconv.FormatFloat(size, 'f', -1, 64) + " bytes", nil
+	}
+	i := -1
+	for i+1 < len(units) && math.Abs(size) >= base {
+		size /= base
+		i++
+	}
+	s := strconv.FormatFloat(size, 'f', prec, 64)
+	// rounding up to the next unit, as 1023.96 KiB to "1024.0"
+	if f, _ := strconv.ParseFloat(s, 64); math.Abs(f) >= base && i+1 < len(units) {
+		size /= base
+		i++
+		s = strconv.FormatFloat(size, 'f', prec, 64)
+	}
+	if t := strings.TrimRight(s, "0"); strings.HasSuffix(t, ".") {
+		s = strings.TrimSuffix(t, ".")
+	}
+	return s + " " + units[i], nil
+}
+
+// fileSizeValue returns v as a number of bytes.
+func fileSizeValue(v interface{}) (float64, error) {
+	if s, ok := v.(string); ok {
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, fmt.Errorf("fileSize: invalid size %q", s)
+		}
+		return f, nil
+	}
+	xs, err := numbers([]interface{}{v})
+	if err != nil {
+		return 0, fmt.Errorf("fileSize: invalid size %v", v)
+	}
+	return xs[0], nil
+}
+
+var fileSizeRe = regexp.MustCompile(`^([0-9]*\.?[0-9]+)\s*([a-zA-Z]*)$`)
+
+// fileSizeUnits are the multipliers of ParseFileSize, by lower case unit.
+var fileSizeUnits = map[string]float64{
+	"": 1, "b": 1, "byte": 1, "bytes": 1,
+	"k": 1 << 10, "kib": 1 << 10, "kb": 1e3,
+	"m": 1 << 20, "mib": 1 << 20, "mb": 1e6,
+	"g": 1 << 30, "gib": 1 << 30, "gb": 1e9,
+	"t": 1 << 40, "tib": 1 << 40, "tb": 1e12,
+	"p": 1 << 50, "pib": 1 << 50, "pb": 1e15,
+	"e": 1 << 60, "eib": 1 << 60, "eb": 1e18,
+}
+
+// ParseFileSize returns the number of bytes of a size such as "1.5GB",
+// "512 KiB" or "10M", rounded to a whole byte. Units are case-insensitive:
+// kB, MB, GB and so on are SI units of 1000, while KiB, MiB, GiB and the
+// single letters K, M, G are units of 1024, as used by ls and dd.
+func ParseFileSize(s string) (int64, error) {
+	m := fileSizeRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("parseFileSize: invalid size %q", s)
+	}
+	mult, ok := fileSizeUnits[strings.ToLower(m[2])]
+	if !ok {
+		return 0, fmt.Errorf("parseFileSize: unknown unit %q", m[2])
+	}
+	f, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("parseFileSize: invalid size %q", s)
+	}
+	n := math.Round(f * mult)
+	if n >= math.MaxInt64 {
+		return 0, fmt.Errorf("parseFileSize: size %q out of range", s)
+	}
+	return int64(n), nil
+}
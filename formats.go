@@ -0,0 +1,83 @@
+package funcmaps
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"path"
+	ttemplate "text/template"
+)
+
+// WithTextFuncs sets the functions available to text templates, instead of
+// TextDefault.
+func WithTextFuncs(fm FuncMap) Option {
+	return func(t *Templates) {
+		t.textFuncs = fm
+	}
+}
+
+// ParseTextFS parses the templates in fsys matching patterns as
+// text/template templates, which are not HTML escaped, for use with
+// ExecuteFormats and ExecuteText.
+func (t *Templates) ParseTextFS(fsys fs.FS, patterns ...string) error {
+	return t.addSource(templateSource{
+		fsys:  fsys,
+		files: func() ([]string, error) { return globFiles(fsys, patterns) },
+		parse: func(set *templateSet) error {
+			_, err := set.text.ParseFS(fsys, patterns...)
+			return err
+		},
+	})
+}
+
+// ExecuteText applies the text template with the given name to data,
+// writing the output to w.
+func (t *Templates) ExecuteText(w io.Writer, name string, data interface{}) error {
+	set, err := t.current()
+	if err != nil {
+		return err
+	}
+	return set.withText(name, func(c *ttemplate.Template) error {
+		return c.ExecuteTemplate(w, name, data)
+	})
+}
+
+// ExecuteFormats renders the sibling templates name+".html" and name+".txt"
+// with the same data, such as the two parts of an email, writing them to
+// htmlW and textW. Both templates must exist, and nothing is written unless
+// both execute successfully.
+func (t *Templates) ExecuteFormats(htmlW, textW io.Writer, name string, data interface{}) error {
+	set, err := t.current()
+	if err != nil {
+		return err
+	}
+	base := path.Clean(name)
+	htmlName, textName := base+".html", base+".txt"
+	if set.text.Lookup(textName) == nil {
+		return fmt.Errorf("text template %q not defined", textName)
+	}
+	var htmlBuf, textBuf bytes.Buffer
+	err = set.with(nil, htmlName, nil, func(c *template.Template) error {
+		return c.ExecuteTemplate(&htmlBuf, htmlName, data)
+	})
+	if err != nil {
+		return err
+	}
+	err = set.withText(textName, func(c *ttemplate.Template) error {
+		return c.ExecuteTemplate(&textBuf, textName, data)
+	})
+	if err != nil {
+		return err
+	}
+	htmlOut, err := ApplyHooks(htmlBuf.Bytes(), t.hooks...)
+	if err != nil {
+		return err
+	}
+	if _, err := htmlW.Write(htmlOut); err != nil {
+		return err
+	}
+	_, err = textBuf.WriteTo(textW)
+	return err
+}
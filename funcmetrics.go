@@ -0,0 +1,131 @@
+package funcmaps
+
+import (
+	"encoding/json"
+	"expvar"
+	"reflect"
+	"sync"
+)
+
+// MetricsRegisterer creates the metrics recorded by InstrumentMetrics, each
+// with a label holding the name of the template function.
+//
+// A Prometheus implementation registers a CounterVec or HistogramVec with
+// the given name and help and a "func" label, and returns a function
+// calling WithLabelValues(funcName).Inc or Observe(seconds):
+//
+//	func (r promRegisterer) Counter(name, help string) func(string) {
+//		v := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, []string{"func"})
+//		r.MustRegister(v)
+//		return func(fn string) { v.WithLabelValues(fn).Inc() }
+//	}
+//
+// ExpvarMetrics is an implementation publishing the metrics with expvar.
+type MetricsRegisterer interface {
+	Counter(name, help string) func(funcName string)
+	Histogram(name, help string) func(funcName string, seconds float64)
+}
+
+// InstrumentMetrics returns a copy of fm whose functions record metrics with
+// reg: the counters funcmaps_calls_total and funcmaps_errors_total, and the
+// histogram funcmaps_call_duration_seconds. This shows which functions
+// templates use most, and which are expensive, such as sanitize.
+func InstrumentMetrics(fm FuncMap, reg MetricsRegisterer) FuncMap {
+	calls := reg.Counter("funcmaps_calls_total", "Calls of template functions.")
+	errs := reg.Counter("funcmaps_errors_total", "Calls of template functions that failed.")
+	latency := reg.Histogram("funcmaps_call_duration_seconds", "Duration of calls of template functions.")
+	return wrapFuncs(fm, observeCall(func(c FuncCall, _, _ []reflect.Value) {
+		latency(c.Name, c.Duration.Seconds())
+		calls(c.Name)
+		if c.Err != nil {
+			errs(c.Name)
+		}
+	}))
+}
+
+// DefaultBuckets are the upper bounds, in seconds, of the histograms of
+// ExpvarMetrics. They are copied when a histogram is registered, so
+// changes only apply to histograms registered later.
+var DefaultBuckets = []float64{.00001, .0001, .001, .01, .1, 1}
+
+// ExpvarMetrics is a MetricsRegisterer publishing metrics as an expvar map,
+// such as {"funcmaps_calls_total": {"sanitize": 12}}.
+type ExpvarMetrics struct {
+	m *expvar.Map
+}
+
+// NewExpvarMetrics returns ExpvarMetrics published in expvar as name.
+// Like expvar.Publish, it panics if name is already in use.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	return &ExpvarMetrics{m: expvar.NewMap(name)}
+}
+
+// Counter returns a function incrementing the count of a function.
+func (e *ExpvarMetrics) Counter(name, help string) func(string) {
+	counts := new(expvar.Map).Init()
+	e.m.Set(name, counts)
+	return func(fn string) { counts.Add(fn, 1) }
+}
+
+// Histogram returns a function adding an observation of a function to a
+// histogram with DefaultBuckets.
+func (e *ExpvarMetrics) Histogram(name, help string) func(string, float64) {
+	buckets := append([]float64(nil), DefaultBuckets...)
+	hists := new(expvar.Map).Init()
+	e.m.Set(name, hists)
+	var mu sync.Mutex // serializes creation of new histograms
+	return func(fn string, v float64) {
+		h, ok := hists.Get(fn).(*histogramVar)
+		if !ok {
+			mu.Lock()
+			if h, ok = hists.Get(fn).(*histogramVar); !ok {
+				h = &histogramVar{buckets: buckets, counts: make([]int64, len(buckets))}
+				hists.Set(fn, h)
+			}
+			mu.Unlock()
+		}
+		h.observe(v)
+	}
+}
+
+// histogramVar is an expvar.Var holding a histogram.
+type histogramVar struct {
+	mu      sync.Mutex
+	buckets []float64 // upper bounds
+	counts  []int64   // per bucket, not cumulative
+	count   int64
+	sum     float64
+}
+
+func (h *histogramVar) observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.count++
+	h.sum += v
+	for i, le := range h.buckets {
+		if v <= le {
+			h.counts[i]++
+			break
+		}
+	}
+}
+
+// String returns the histogram as JSON, with cumulative bucket counts keyed
+// by their upper bound, as required by expvar.Var.
+func (h *histogramVar) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	buckets := make(map[string]int64, len(h.buckets))
+	var n int64
+	for i, le := range h.buckets {
+		n += h.counts[i]
+		b, _ := json.Marshal(le)
+		buckets[string(b)] = n
+	}
+	b, _ := json.Marshal(map[string]interface{}{
+		"count":   h.count,
+		"sum":     h.sum,
+		"buckets": buckets,
+	})
+	return string(b)
+}
@@ -1,6 +1,7 @@
 package funcmaps
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,34 +13,142 @@ import (
 
 type FuncMap map[string]interface{}
 
-func Default() FuncMap {
-	return FuncMap{
-		"toLower":     strings.ToLower,
-		"toUpper":     strings.ToUpper,
-		"toTitle":     strings.ToTitle,
-		"string":      func(v interface{}) string { return fmt.Sprintf("%v", v) },
-		"trim":        func(c, s string) string { return strings.Trim(s, c) },
-		"trimspace":   strings.TrimSpace,
-		"trim_left":   func(c, s string) string { return strings.TrimLeft(s, c) },
-		"trim_right":  func(c, s string) string { return strings.TrimRight(s, c) },
-		"trim_prefix": func(c, s string) string { return strings.TrimPrefix(s, c) },
-		"trim_suffix": func(c, s string) string { return strings.TrimSuffix(s, c) },
-		"title":       strings.Title,
+// Placeholder is the text returned by default for missing or invalid data
+// by int, file_size, fileSize and join2, such as "—" or "N/A". It is read
+// when the FuncMap is created; WithPlaceholder overrides it for one
+// FuncMap.
+var Placeholder = ""
+
+// MapOption configures the functions of a FuncMap constructor.
+type MapOption func(*mapOptions)
+
+type mapOptions struct {
+	placeholder string
+	location    *time.Location  // of now, and of date without a zone
+	edits       []func(FuncMap) // applied in order to the finished map
+}
+
+func newMapOptions(opts []MapOption) *mapOptions {
+	o := &mapOptions{placeholder: Placeholder, location: time.Local}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// or returns s, or the placeholder if s is empty.
+func (o *mapOptions) or(s string) string {
+	if s == "" {
+		return o.placeholder
+	}
+	return s
+}
+
+// edit applies the Without, Only and Rename options to fm.
+func (o *mapOptions) edit(fm FuncMap) FuncMap {
+	for _, e := range o.edits {
+		e(fm)
+	}
+	return fm
+}
+
+// Without removes the functions with the given names, such as env or uuid
+// from maps used for untrusted or reproducible output, and their aliases.
+func Without(names ...string) MapOption {
+	return func(o *mapOptions) {
+		o.edits = append(o.edits, func(fm FuncMap) {
+			for _, name := range names {
+				dropAliases(fm, name)
+				delete(fm, name)
+			}
+		})
+	}
+}
+
+// Only removes every function except those with the given names.
+func Only(names ...string) MapOption {
+	return func(o *mapOptions) {
+		o.edits = append(o.edits, func(fm FuncMap) {
+			keep := make(map[string]bool, len(names))
+			for _, name := range names {
+				keep[name] = true
+			}
+			for name := range fm {
+				if !keep[name] {
+					delete(fm, name)
+				}
+			}
+		})
+	}
+}
+
+// Rename gives the function named old the name new, replacing any function
+// with that name, to resolve conflicts with other FuncMaps. It does nothing
+// if there is no function named old. The aliases of old are removed.
+func Rename(old, new string) MapOption {
+	return func(o *mapOptions) {
+		o.edits = append(o.edits, func(fm FuncMap) {
+			if fn, ok := fm[old]; ok && old != new {
+				dropAliases(fm, old)
+				delete(fm, old)
+				fm[new] = fn
+			}
+		})
+	}
+}
+
+// WithPlaceholder sets the text returned for missing or invalid data,
+// instead of Placeholder.
+func WithPlaceholder(s string) MapOption {
+	return func(o *mapOptions) {
+		o.placeholder = s
+	}
+}
+
+// Default returns the general purpose functions of the package.
+//
+// The string functions, such as toLower, trim and has_prefix, take
+// strings; use AcceptBytes to have them accept byte slices and other
+// values too.
+func Default(opts ...MapOption) FuncMap {
+	o := newMapOptions(opts)
+	return o.edit(defaultFuncs(o))
+}
+
+// defaultFuncs returns the functions of Default, with their aliases.
+func defaultFuncs(o *mapOptions) FuncMap {
+	return recordRisks(addAliases(FuncMap{
+		"toLower":     withBytes(strings.ToLower, textFunc(strings.ToLower)),
+		"toUpper":     withBytes(strings.ToUpper, textFunc(strings.ToUpper)),
+		"toTitle":     withBytes(strings.ToTitle, textFunc(strings.ToTitle)),
+		"string":      stringifyValue,
+		"trim":        withBytes(func(c, s string) string { return strings.Trim(s, c) }, textArgFunc(strings.Trim)),
+		"trimspace":   withBytes(strings.TrimSpace, textFunc(strings.TrimSpace)),
+		"trim_left":   withBytes(func(c, s string) string { return strings.TrimLeft(s, c) }, textArgFunc(strings.TrimLeft)),
+		"trim_right":  withBytes(func(c, s string) string { return strings.TrimRight(s, c) }, textArgFunc(strings.TrimRight)),
+		"trim_prefix": withBytes(func(c, s string) string { return strings.TrimPrefix(s, c) }, textArgFunc(strings.TrimPrefix)),
+		"trim_suffix": withBytes(func(c, s string) string { return strings.TrimSuffix(s, c) }, textArgFunc(strings.TrimSuffix)),
+		"title":       withBytes(strings.Title, textFunc(strings.Title)),
 		"fields":      strings.Fields,
-		"wc":          func(s string) int { return len(strings.Fields(s)) },
-		"has_prefix":  func(c, s string) bool { return strings.HasPrefix(s, c) },
-		"has_suffix":  func(c, s string) bool { return strings.HasSuffix(s, c) },
+		"wc":          withBytes(func(s string) int { return len(strings.Fields(s)) }, wordCount),
+		"has_prefix":  withBytes(func(c, s string) bool { return strings.HasPrefix(s, c) }, textPredicate(strings.HasPrefix, bytes.HasPrefix)),
+		"has_suffix":  withBytes(func(c, s string) bool { return strings.HasSuffix(s, c) }, textPredicate(strings.HasSuffix, bytes.HasSuffix)),
 		"replace":     func(old, new string, n int, s string) string { return strings.Replace(s, old, new, n) },
 		"replace_all": func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
-		"count":       func(sub, s string) int { return strings.Count(s, sub) },
+		"count":       withBytes(func(sub, s string) int { return strings.Count(s, sub) }, countText),
 		"split":       func(sep, s string) []string { return strings.Split(s, sep) },
 		"split_n":     func(sep string, n int, s string) []string { return strings.SplitN(s, sep, n) },
 		"backtick":    func(s interface{}) string { return fmt.Sprintf("`%v`", s) },
 		"backticks":   func(lang string, s interface{}) string { return fmt.Sprintf("```%s\n%v\n```", lang, s) },
-		"date":        FormatTime,
-		"contains":    strings.Contains,
-		"now":         time.Now,
-		"NOW":         func() string { return time.Now().String() },
+		"date": func(layout, zone string, date interface{}) string {
+			return formateDate(layout, date, zone, o.location, timeLocale("en"))
+		},
+		"date_locale": func(layout, zone, lang string, date interface{}) string {
+			return formateDate(layout, date, zone, o.location, timeLocale(lang))
+		},
+		"contains": strings.Contains,
+		"now":      func() time.Time { return time.Now().In(o.location) },
+		"NOW":      func() string { return time.Now().In(o.location).String() },
 		"json": func(v interface{}) string {
 			a, _ := json.Marshal(v)
 			return string(a)
@@ -58,23 +167,16 @@ func Default() FuncMap {
 		// yaml
 		// xml
 		// toml
-		"join": strings.Join,
-		"unexport": func(input string) string {
-			return fmt.Sprintf("%s%s", strings.ToLower(input[0:1]), input[1:])
-		},
-		"add":   func(a, b int) int { return a + b },
-		"lower": strings.ToLower,
-		"upper": strings.ToUpper,
-		"rev": func(v interface{}) string {
-			runes := []rune(v.(string))
-			for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
-				runes[i], runes[j] = runes[j], runes[i]
-			}
-			return string(runes)
-		},
+		"join":     strings.Join,
+		"unexport": Unexport,
+		"add":      func(a, b int) int { return a + b },
+		"rev":      func(v interface{}) string { return GraphemeReverse(stringifyValue(v)) },
 		"int": func(v interface{}) string {
-			a, err := strconv.Atoi(v.(string))
+			a, err := strconv.Atoi(fmt.Sprintf("%v", v))
 			if err != nil {
+				if o.placeholder != "" {
+					return o.placeholder
+				}
 				return fmt.Sprintf("%v", v)
 			}
 			return fmt.Sprintf("%d", a)
@@ -82,20 +184,38 @@ func Default() FuncMap {
 		"is_true":    IsTrue,
 		"is_empty":   IsEmpty,
 		"is_default": IsDefault,
+		"present":    Present,
+		"blank":      Blank,
+		"anyPresent": AnyPresent,
 		"yesno":      YesNo,
-		"ternary":    YesNo,
 		"coalesce":   Coalesce,
 		"env":        os.Getenv,
 		"has":        Has,
 		"has_any":    HasAny,
-		"file_size":  FileSizeFormat,
-		"uuid":       UUID,
-		"repeat":     Repeat,
-		"join2":      Join2,
-		"eq_any":     EqualAny,
-		"deep_eq":    reflect.DeepEqual,
-		"map":        Map,
-	}
+		"file_size":  func(v interface{}) string { return o.or(FileSizeFormat(v)) },
+		"fileSize": func(v interface{}, opts ...interface{}) (string, error) {
+			s, err := FileSize(v, opts...)
+			return o.or(s), err
+		},
+		"parseFileSize": ParseFileSize,
+		"uuid":          UUID,
+		"repeat":        Repeat,
+		"repeat_n":      RepeatN,
+		"seq":           Seq,
+		"until":         Until,
+		"newScratch":    NewScratch,
+		"join2":         func(sep string, values ...interface{}) string { return o.or(Join2(sep, values...)) },
+		"eq_any":        EqualAny,
+		"deep_eq":       reflect.DeepEqual,
+		"map":           Map,
+	}, aliases))
+}
+
+// TextDefault returns the functions for plain text output, as used for text
+// templates by Templates. It is currently the same as Default, none of
+// whose functions produce markup.
+func TextDefault(opts ...MapOption) FuncMap {
+	return Default(opts...)
 }
 
 func Combined(fs ...FuncMap) FuncMap {
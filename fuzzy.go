@@ -0,0 +1,81 @@
+package funcmaps
+
+import "unicode"
+
+// Fuzzy returns functions comparing strings, for "did you mean"
+// suggestions and search results:
+//
+//	levenshtein "kitten" "sitting"  => 3
+//	similarity "kitten" "sitting"   => 0.5714285714285714
+//	fuzzyMatch "fb" "FooBar"        => true
+func Fuzzy() FuncMap {
+	return FuncMap{
+		"levenshtein": Levenshtein,
+		"similarity":  Similarity,
+		"fuzzyMatch":  FuzzyMatch,
+	}
+}
+
+// Levenshtein returns the number of rune insertions, deletions and
+// substitutions needed to change a into b.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		diag := row[0]
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			next := min3(row[j]+1, row[j-1]+1, diag+cost)
+			diag, row[j] = row[j], next
+		}
+	}
+	return row[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// Similarity returns how similar a and b are, from 0 for nothing in common
+// to 1 for equal strings, as one minus their Levenshtein distance relative
+// to the length of the longer one.
+func Similarity(a, b string) float64 {
+	n := len([]rune(a))
+	if m := len([]rune(b)); m > n {
+		n = m
+	}
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(n)
+}
+
+// FuzzyMatch reports whether the runes of pattern appear in s in the same
+// order, ignoring case, as in the file finders of editors: "fb" matches
+// "FooBar" and "fileBrowser" but not "bf".
+func FuzzyMatch(pattern, s string) bool {
+	rs := []rune(pattern)
+	i := 0
+	for _, r := range s {
+		if i == len(rs) {
+			break
+		}
+		if unicode.ToLower(r) == unicode.ToLower(rs[i]) {
+			i++
+		}
+	}
+	return i == len(rs)
+}
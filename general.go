@@ -4,13 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"io"
+	"math"
 	"reflect"
 	"strings"
+	"time"
 
 	"unicode"
 
 	"github.com/google/uuid"
 	"github.com/microcosm-cc/bluemonday"
+	"golang.org/x/text/unicode/norm"
 )
 
 // most of this file copied from https://github.com/pthethanh/template/blob/master/funcs.go
@@ -227,39 +231,160 @@ func EqualAny(v interface{}, values ...interface{}) bool {
 // Repeat repeats the string representation of value n times.
 func Repeat(n int, v interface{}) string {
 	rs := &strings.Builder{}
+	WriteRepeat(rs, n, v)
+	return rs.String()
+}
+
+// RepeatN is Repeat, returning an error instead when n is more than max.
+func RepeatN(max, n int, v interface{}) (string, error) {
+	if n > max {
+		return "", fmt.Errorf("repeat count %d exceeds the maximum of %d", n, max)
+	}
+	return Repeat(n, v), nil
+}
+
+// Seq returns the integers from start to end, inclusive, counting by step,
+// which defaults to 1, or -1 when end is less than start:
+//
+//	seq 1 5     => [1 2 3 4 5]
+//	seq 10 0 -5 => [10 5 0]
+//
+// A step going away from end gives an empty list, and sequences longer
+// than 1<<24 integers are refused.
+func Seq(start, end int, step ...int) ([]int, error) {
+	n, by, err := seqLen(start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]int, n)
+	for i := range rs {
+		rs[i] = start + i*by
+	}
+	return rs, nil
+}
+
+// maxSeqLen is the length of the longest sequence of Seq.
+const maxSeqLen = 1 << 24
+
+// seqLen returns the length and step of the sequence of Seq.
+func seqLen(start, end int, step []int) (int, int, error) {
+	by := 1
+	if end < start {
+		by = -1
+	}
+	switch len(step) {
+	case 0:
+	case 1:
+		by = step[0]
+	default:
+		return 0, 0, fmt.Errorf("seq: too many arguments")
+	}
+	if by == 0 {
+		return 0, 0, fmt.Errorf("seq: step must not be 0")
+	}
+	if by > 0 && end < start || by < 0 && end > start {
+		return 0, by, nil
+	}
+	// the distance and step as unsigned magnitudes, which do not overflow
+	span, abs := uint64(end)-uint64(start), uint64(by)
+	if by < 0 {
+		span, abs = uint64(start)-uint64(end), -uint64(by)
+	}
+	if steps := span / abs; steps >= maxSeqLen {
+		return 0, 0, fmt.Errorf("seq: sequence of more than %d integers", maxSeqLen)
+	}
+	return int(span/abs + 1), by, nil
+}
+
+// Until returns the integers from 0 to n-1, for looping n times:
+//
+//	{{range until 3}}<td></td>{{end}}
+func Until(n int) []int {
+	if n < 0 {
+		n = 0
+	}
+	rs := make([]int, n)
+	for i := range rs {
+		rs[i] = i
+	}
+	return rs
+}
+
+// WriteRepeat writes the string representation of value to w n times.
+func WriteRepeat(w io.Writer, n int, v interface{}) error {
+	if n <= 0 {
+		return nil
+	}
+	s := stringify(reflect.ValueOf(v))
+	if b, ok := w.(*strings.Builder); ok && len(s) <= math.MaxInt32/n {
+		b.Grow(len(s) * n)
+	}
 	for i := 0; i < n; i++ {
-		rs.WriteString(fmt.Sprintf("%v", printableValue(reflect.ValueOf(v))))
+		if _, err := io.WriteString(w, s); err != nil {
+			return err
+		}
 	}
-	return rs.String()
+	return nil
 }
 
 // Join join the string representation of the values together.
 // String will be joined as whole.
 // Map, slice, array will be joined using its value, one by one.
 func Join2(sep string, values ...interface{}) string {
-	rs := make([]string, 0)
-	for _, val := range values {
+	b := getBuffer()
+	defer putBuffer(b)
+	WriteJoin2(b, sep, values...)
+	return b.String()
+}
+
+// WriteJoin2 writes the values joined as by Join2 to w, without building
+// the whole string in memory.
+func WriteJoin2(w io.Writer, sep string, values ...interface{}) error {
+	rvs := make([]reflect.Value, len(values))
+	for i, val := range values {
 		v, isNil := indirect(reflect.ValueOf(val))
 		if isNil {
-			return ""
+			return nil
+		}
+		rvs[i] = v
+	}
+	first := true
+	write := func(s string) error {
+		if !first {
+			if _, err := io.WriteString(w, sep); err != nil {
+				return err
+			}
 		}
+		first = false
+		_, err := io.WriteString(w, s)
+		return err
+	}
+	for _, v := range rvs {
 		switch v.Kind() {
 		case reflect.String:
-			rs = append(rs, v.String())
+			if err := write(v.String()); err != nil {
+				return err
+			}
 		case reflect.Array, reflect.Slice:
 			for i := 0; i < v.Len(); i++ {
-				rs = append(rs, fmt.Sprintf("%v", printableValue(v.Index(i))))
+				if err := write(stringify(v.Index(i))); err != nil {
+					return err
+				}
 			}
 		case reflect.Map:
 			r := v.MapRange()
 			for r.Next() {
-				rs = append(rs, fmt.Sprintf("%v", printableValue(r.Value())))
+				if err := write(stringify(r.Value())); err != nil {
+					return err
+				}
 			}
 		default:
-			rs = append(rs, fmt.Sprintf("%v", printableValue(v)))
+			if err := write(stringify(v)); err != nil {
+				return err
+			}
 		}
 	}
-	return strings.Join(rs, sep)
+	return nil
 }
 
 // Has check whether all the values exist in the collection.
@@ -293,7 +418,7 @@ func has(collection reflect.Value, val reflect.Value) bool {
 	switch v.Kind() {
 	case reflect.String:
 		// accept all kinds of val.
-		return strings.Contains(v.String(), fmt.Sprintf("%v", val))
+		return strings.Contains(v.String(), stringify(val))
 	case reflect.Array, reflect.Slice:
 		for i := 0; i < v.Len(); i++ {
 			iv, vIsNil := indirect(v.Index(i))
@@ -365,12 +490,55 @@ func IsEmpty(v interface{}) bool {
 	return !IsTrue(v)
 }
 
+// Blank reports whether v holds no meaningful value: nil, a nil pointer,
+// false, a string of only whitespace, an empty slice or map, or a zero
+// time.Time. Pointers are followed. Unlike IsEmpty, numbers are never blank,
+// since zero is usually a meaningful value in API data.
+func Blank(v interface{}) bool {
+	rv, isNil := indirect(reflect.ValueOf(v))
+	if isNil || !rv.IsValid() {
+		return true
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.String:
+		return strings.TrimSpace(rv.String()) == ""
+	case reflect.Array, reflect.Slice, reflect.Map, reflect.Chan:
+		return rv.Len() == 0
+	case reflect.Func:
+		return rv.IsNil()
+	case reflect.Struct:
+		if t, ok := rv.Interface().(time.Time); ok {
+			return t.IsZero()
+		}
+	}
+	return false
+}
+
+// Present is the opposite of Blank.
+func Present(v interface{}) bool {
+	return !Blank(v)
+}
+
+// AnyPresent reports whether any of the values is Present.
+func AnyPresent(v ...interface{}) bool {
+	for _, val := range v {
+		if Present(val) {
+			return true
+		}
+	}
+	return false
+}
+
 // UUID return a UUID.
 func UUID() string {
 	return uuid.New().String()
 }
 
-// FileSizeFormat return human readable string of file size.
+// FileSizeFormat return human readable string of file size, in units of
+// 1024 bytes labelled KB, MB and so on; see FileSize for correctly labelled
+// IEC or SI units.
 func FileSizeFormat(value interface{}) string {
 	var size float64
 
@@ -419,7 +587,7 @@ func Map(v ...interface{}) map[string]interface{} {
 	m := map[string]interface{}{}
 	lv := len(v)
 	for i := 0; i < lv; i += 2 {
-		key := fmt.Sprintf("%v", v[i])
+		key := stringifyValue(v[i])
 		if i+1 >= lv {
 			m[key] = ""
 			continue
@@ -439,14 +607,16 @@ var (
 var ASCIISpace = rune(` `[0])
 
 // StripTags allows everything, allows tabs, newlines, ASCII space but
-// non-conforming whitespace, control chars, and also prevents shouting
+// non-conforming whitespace, control chars, and also prevents shouting.
+// The text is normalized to NFC first.
 func StripTags(s string) string {
-	return textPolicy.Sanitize(stripChars(s, true, true, true, true))
+	return textPolicy.Sanitize(stripChars(norm.NFC.String(s), true, true, true, true))
 }
 
-// StripTagsSentence strips all HTML tags, allows ASCII space
+// StripTagsSentence strips all HTML tags, allows ASCII space.
+// The text is normalized to NFC first.
 func StripTagsSentence(s string) string {
-	return textPolicy.Sanitize(stripChars(s, true, true, false, true))
+	return textPolicy.Sanitize(stripChars(norm.NFC.String(s), true, true, false, true))
 }
 
 // SanitiseHTML sanitizes HTML
@@ -0,0 +1,81 @@
+package funcmaps
+
+import (
+	"io"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func BenchmarkRepeat(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Repeat(1000, "abc")
+	}
+}
+
+func BenchmarkWriteRepeat(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		WriteRepeat(io.Discard, 1000, "abc")
+	}
+}
+
+func BenchmarkJoin2(b *testing.B) {
+	values := []interface{}{"a", []string{"b", "c", "d"}, map[string]int{"e": 1, "f": 2}, 42}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Join2(", ", values...)
+	}
+}
+
+func BenchmarkWriteJoin2(b *testing.B) {
+	values := []interface{}{"a", []string{"b", "c", "d"}, map[string]int{"e": 1, "f": 2}, 42}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		WriteJoin2(io.Discard, ", ", values...)
+	}
+}
+
+func TestSeq(t *testing.T) {
+	for _, tt := range []struct {
+		start, end int
+		step       []int
+		want       []int
+	}{
+		{1, 5, nil, []int{1, 2, 3, 4, 5}},
+		{10, 0, []int{-5}, []int{10, 5, 0}},
+		{3, 1, nil, []int{3, 2, 1}},
+		{1, 5, []int{-1}, []int{}},
+		{math.MaxInt - 1, math.MaxInt, nil, []int{math.MaxInt - 1, math.MaxInt}},
+		{math.MinInt, math.MinInt + 4, []int{2}, []int{math.MinInt, math.MinInt + 2, math.MinInt + 4}},
+		{math.MinInt, math.MaxInt, []int{math.MaxInt}, []int{math.MinInt, -1, math.MaxInt - 1}},
+	} {
+		got, err := Seq(tt.start, tt.end, tt.step...)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Seq(%d, %d, %v) = %v, %v, want %v", tt.start, tt.end, tt.step, got, err, tt.want)
+		}
+	}
+	for _, tt := range [][3]int{
+		{0, math.MaxInt, 1},
+		{math.MinInt, math.MaxInt, 1},
+		{math.MaxInt, math.MinInt, -1},
+		{0, 1, 0},
+	} {
+		if _, err := Seq(tt[0], tt[1], tt[2]); err == nil {
+			t.Errorf("Seq(%d, %d, %d): got nil error", tt[0], tt[1], tt[2])
+		}
+	}
+}
+
+func TestSeqLimit(t *testing.T) {
+	fm := Limit(Default(), LimitOptions{MaxOutputBytes: 800})
+	if got, err := execute(fm, `{{len (seq 1 100)}}`, nil); err != nil || got != "100" {
+		t.Errorf("seq 1 100: got %q, %v", got, err)
+	}
+	for _, text := range []string{`{{seq 1 101}}`, `{{seq 0 9223372036854775807}}`} {
+		if _, err := execute(fm, text, nil); err == nil {
+			t.Errorf("%s: got nil error", text)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package funcmaps
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Build provenance injected at build time, used by Git in place of reading
+// the repository:
+//
+//	go build -ldflags "-X github.com/aerth/funcmaps.GitSHA=$(git rev-parse HEAD)"
+//
+// GitCommitTime is in RFC 3339 format.
+var (
+	GitSHA        string
+	GitTag        string
+	GitBranch     string
+	GitCommitTime string
+	GitDescribe   string
+)
+
+// shortSHALength is the length of the SHAs returned by gitShortSHA.
+const shortSHALength = 7
+
+// Git returns functions giving the commit a program was built from, for
+// changelog and version pages: gitSHA, gitShortSHA, gitTag, gitBranch,
+// gitCommitTime and gitDescribe.
+//
+// Values injected in the Git variables at build time are used when set.
+// Others are read once from the repository at repoPath with the git
+// command; an empty repoPath only uses injected values. gitTag is empty
+// unless a tag points at the commit, and gitBranch when HEAD is detached.
+func Git(repoPath string) FuncMap {
+	g := &gitRepo{path: repoPath}
+	get := func(field func(*gitInfo) string) func() (string, error) {
+		return func() (string, error) {
+			info, err := g.info()
+			if err != nil {
+				return "", err
+			}
+			return field(info), nil
+		}
+	}
+	return recordRisks(FuncMap{
+		"gitSHA": get(func(i *gitInfo) string { return i.sha }),
+		"gitShortSHA": get(func(i *gitInfo) string {
+			if len(i.sha) > shortSHALength {
+				return i.sha[:shortSHALength]
+			}
+			return i.sha
+		}),
+		"gitTag":      get(func(i *gitInfo) string { return i.tag }),
+		"gitBranch":   get(func(i *gitInfo) string { return i.branch }),
+		"gitDescribe": get(func(i *gitInfo) string { return i.describe }),
+		"gitCommitTime": func() (time.Time, error) {
+			info, err := g.info()
+			if err != nil {
+				return time.Time{}, err
+			}
+			if info.commitTime == "" {
+				return time.Time{}, nil
+			}
+			return time.Parse(time.RFC3339, info.commitTime)
+		},
+	})
+}
+
+type gitInfo struct {
+	sha, tag, branch, commitTime, describe string
+}
+
+// gitRepo reads gitInfo from a repository once.
+type gitRepo struct {
+	path string
+	once sync.Once
+	rs   *gitInfo
+	err  error
+}
+
+func (g *gitRepo) info() (*gitInfo, error) {
+	g.once.Do(func() {
+		g.rs, g.err = g.read()
+	})
+	return g.rs, g.err
+}
+
+func (g *gitRepo) read() (*gitInfo, error) {
+	info := &gitInfo{
+		sha:        GitSHA,
+		tag:        GitTag,
+		branch:     GitBranch,
+		commitTime: GitCommitTime,
+		describe:   GitDescribe,
+	}
+	if g.path == "" {
+		return info, nil
+	}
+	fields := []struct {
+		v        *string
+		args     []string
+		optional bool // failure means there is no value
+	}{
+		{&info.sha, []string{"rev-parse", "HEAD"}, false},
+		{&info.tag, []string{"describe", "--tags", "--exact-match"}, true},
+		{&info.branch, []string{"symbolic-ref", "--short", "-q", "HEAD"}, true},
+		{&info.commitTime, []string{"log", "-1", "--format=%cI"}, false},
+		{&info.describe, []string{"describe", "--tags", "--always", "--dirty"}, false},
+	}
+	for _, f := range fields {
+		if *f.v != "" {
+			continue
+		}
+		out, err := g.git(f.args...)
+		if err != nil && !f.optional {
+			return nil, err
+		}
+		*f.v = out
+	}
+	return info, nil
+}
+
+func (g *gitRepo) git(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", g.path}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
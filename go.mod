@@ -1,10 +1,21 @@
 module github.com/aerth/funcmaps
 
-go 1.15
+go 1.17
 
 require (
+	github.com/boombuler/barcode v1.0.1
 	github.com/google/uuid v1.1.2
 	github.com/kr/pretty v0.2.1
 	github.com/microcosm-cc/bluemonday v1.0.4
 	github.com/spf13/cast v1.3.1
+	golang.org/x/net v0.0.0-20181220203305-927f97764cc3
+	golang.org/x/text v0.3.8
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require (
+	github.com/aymerick/douceur v0.2.0 // indirect
+	github.com/chris-ramon/douceur v0.2.0 // indirect
+	github.com/gorilla/css v1.0.0 // indirect
+	github.com/kr/text v0.1.0 // indirect
 )
@@ -0,0 +1,113 @@
+package funcmaps
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Graphemes splits s into user-perceived characters, so that emoji with
+// modifiers or zero width joiners, flags, combining marks and CRLF are kept
+// whole. It implements the commonly needed rules of Unicode text
+// segmentation (UAX #29), not all of them.
+func Graphemes(s string) []string {
+	var rs []string
+	for s != "" {
+		n := graphemeLen(s)
+		rs = append(rs, s[:n])
+		s = s[n:]
+	}
+	return rs
+}
+
+// GraphemeLen returns the number of user-perceived characters of s.
+func GraphemeLen(s string) int {
+	n := 0
+	for s != "" {
+		s = s[graphemeLen(s):]
+		n++
+	}
+	return n
+}
+
+// GraphemeTrunc returns the first n user-perceived characters of s.
+func GraphemeTrunc(n int, s string) string {
+	i := 0
+	for ; n > 0 && i < len(s); n-- {
+		i += graphemeLen(s[i:])
+	}
+	return s[:i]
+}
+
+// GraphemeReverse reverses the user-perceived characters of s.
+func GraphemeReverse(s string) string {
+	gs := Graphemes(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := len(gs) - 1; i >= 0; i-- {
+		b.WriteString(gs[i])
+	}
+	return b.String()
+}
+
+// FirstGrapheme returns the first user-perceived character of s, or "".
+func FirstGrapheme(s string) string {
+	if s == "" {
+		return ""
+	}
+	return s[:graphemeLen(s)]
+}
+
+// graphemeLen returns the length in bytes of the first grapheme cluster of
+// the non-empty string s.
+func graphemeLen(s string) int {
+	r, n := utf8.DecodeRuneInString(s)
+	if r == '\r' && n < len(s) && s[n] == '\n' {
+		return n + 1
+	}
+	if r == '\r' || r == '\n' || unicode.IsControl(r) {
+		return n
+	}
+	prev := r
+	regional := isRegional(r)
+	for n < len(s) {
+		next, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case isGraphemeExtend(next):
+		case prev == 0x200D && isPictographic(next): // zero width joiner sequence
+		case regional && isRegional(next): // the second half of a flag
+		case isHangulL(prev) && (isHangulL(next) || isHangulV(next)),
+			isHangulV(prev) && (isHangulV(next) || isHangulT(next)),
+			(isHangulT(prev) || isHangulSyllable(prev)) && isHangulT(next):
+		default:
+			return n
+		}
+		regional = false // flags are pairs
+		prev = next
+		n += size
+	}
+	return n
+}
+
+// isGraphemeExtend reports whether r extends the preceding cluster:
+// combining marks, variation selectors, emoji modifiers, zero width
+// joiners and emoji tags.
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r >= 0xFE00 && r <= 0xFE0F || // variation selectors
+		r >= 0x1F3FB && r <= 0x1F3FF || // emoji skin tone modifiers
+		r >= 0xE0020 && r <= 0xE007F || // tags, as in subdivision flags
+		r == 0x200D
+}
+
+func isPictographic(r rune) bool {
+	return unicode.Is(unicode.So, r) || r >= 0x1F000 && r <= 0x1FAFF || r >= 0x2600 && r <= 0x27BF
+}
+
+func isRegional(r rune) bool { return r >= 0x1F1E6 && r <= 0x1F1FF }
+
+func isHangulL(r rune) bool { return r >= 0x1100 && r <= 0x115F || r >= 0xA960 && r <= 0xA97C }
+func isHangulV(r rune) bool { return r >= 0x1160 && r <= 0x11A7 || r >= 0xD7B0 && r <= 0xD7C6 }
+func isHangulT(r rune) bool { return r >= 0x11A8 && r <= 0x11FF || r >= 0xD7CB && r <= 0xD7FB }
+
+func isHangulSyllable(r rune) bool { return r >= 0xAC00 && r <= 0xD7A3 }
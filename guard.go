@@ -0,0 +1,149 @@
+package funcmaps
+
+import (
+	"fmt"
+	"html/template"
+	"reflect"
+)
+
+// ArgGuard checks the arguments of a function call before it is made,
+// returning an error to refuse the call.
+type ArgGuard func(args []interface{}) error
+
+// DefaultGuards limit the arguments of the functions of this package whose
+// cost grows with their input, for use with Guard.
+var DefaultGuards = map[string]ArgGuard{
+	"sanitize":          MaxStringLen(1 << 20),
+	"stripTags":         MaxStringLen(1 << 20),
+	"stripTagsSentence": MaxStringLen(1 << 20),
+	"highlight":         MaxStringLen(1 << 20),
+	"minifyHTML":        MaxStringLen(1 << 20),
+	"minifyCSS":         MaxStringLen(1 << 20),
+	"minifyJS":          MaxStringLen(1 << 20),
+	"diff":              MaxStringLen(1 << 18),
+	"diffHTML":          MaxStringLen(1 << 18),
+	"repeat":            MaxRepeatLen(1 << 20),
+	"repeat_n":          MaxRepeatLen(1 << 20),
+	"seq":               MaxSeqLen(10000),
+	"until":             MaxIntArg(10000),
+	"levenshtein":       MaxStringLen(1 << 12),
+	"similarity":        MaxStringLen(1 << 12),
+	"histogram":         MaxIntArg(1000),
+}
+
+// Guard returns a copy of fm whose functions named in guards check their
+// arguments first, so that a single malicious or buggy value cannot blow
+// up memory or time during a render:
+//
+//	fm := funcmaps.Guard(funcmaps.All(), funcmaps.DefaultGuards)
+func Guard(fm FuncMap, guards map[string]ArgGuard) FuncMap {
+	rs := make(FuncMap, len(fm))
+	for name, f := range fm {
+		guard, ok := guards[name]
+		if !ok {
+			rs[name] = f
+			continue
+		}
+		typ := reflect.TypeOf(f)
+		rs[name] = wrapFunc(name, f, func(name string, args []reflect.Value, next func([]reflect.Value) []reflect.Value) []reflect.Value {
+			if err := guard(callArgs(typ, args)); err != nil {
+				return failCall(typ, err)
+			}
+			return next(args)
+		})
+	}
+	return rs
+}
+
+// MaxStringLen returns an ArgGuard refusing string, byte slice and
+// html/template content arguments longer than max bytes.
+func MaxStringLen(max int) ArgGuard {
+	return func(args []interface{}) error {
+		for _, a := range args {
+			n := -1
+			switch a := a.(type) {
+			case string:
+				n = len(a)
+			case []byte:
+				n = len(a)
+			case template.HTML:
+				n = len(a)
+			case template.CSS:
+				n = len(a)
+			case template.JS:
+				n = len(a)
+			}
+			if n > max {
+				return fmt.Errorf("argument of %d bytes exceeds the limit of %d", n, max)
+			}
+		}
+		return nil
+	}
+}
+
+// MaxIntArg returns an ArgGuard refusing integer arguments greater than
+// max, such as counts.
+func MaxIntArg(max int64) ArgGuard {
+	return func(args []interface{}) error {
+		for _, a := range args {
+			v := reflect.ValueOf(a)
+			switch v.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				if v.Int() > max {
+					return fmt.Errorf("argument %d exceeds the limit of %d", v.Int(), max)
+				}
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				if v.Uint() > uint64(max) {
+					return fmt.Errorf("argument %d exceeds the limit of %d", v.Uint(), max)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// MaxRepeatLen returns an ArgGuard refusing calls of repeat and repeat_n
+// that would return more than max bytes, whatever the count and the value,
+// so that nested calls are bounded too. Every repetition counts for at
+// least one byte, so that repeating an empty string is bounded in time.
+func MaxRepeatLen(max int) ArgGuard {
+	return func(args []interface{}) error {
+		if len(args) < 2 {
+			return nil
+		}
+		n, ok := args[len(args)-2].(int)
+		if !ok || n <= 0 {
+			return nil
+		}
+		size := len(stringify(reflect.ValueOf(args[len(args)-1])))
+		if size == 0 {
+			size = 1
+		}
+		if n > max/size {
+			return fmt.Errorf("%d repetitions exceed the limit of %d bytes", n, max)
+		}
+		return nil
+	}
+}
+
+// MaxSeqLen returns an ArgGuard refusing calls of seq that would return
+// more than max integers.
+func MaxSeqLen(max int) ArgGuard {
+	return func(args []interface{}) error {
+		if len(args) < 2 {
+			return nil
+		}
+		start, _ := args[0].(int)
+		end, _ := args[1].(int)
+		var step []int
+		for _, a := range args[2:] {
+			if by, ok := a.(int); ok {
+				step = append(step, by)
+			}
+		}
+		if n, _, err := seqLen(start, end, step); err == nil && n > max {
+			return fmt.Errorf("sequence of %d integers exceeds the limit of %d", n, max)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package funcmaps
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// execute parses and executes text with fm and data.
+func execute(fm FuncMap, text string, data interface{}) (string, error) {
+	t, err := template.New("t").Funcs(template.FuncMap(fm)).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
+	return buf.String(), err
+}
+
+func TestGuard(t *testing.T) {
+	fm := Guard(Default(), DefaultGuards)
+	for _, tt := range []struct {
+		text    string
+		want    string
+		wantErr string
+	}{
+		{`{{repeat 3 "ab"}}`, "ababab", ""},
+		{`{{repeat 1000000000 "x"}}`, "", "exceed the limit"},
+		{`{{repeat 1000 (repeat 1000 "xx")}}`, "", "exceed the limit"},
+		{`{{len (seq 1 10)}}`, "10", ""},
+		{`{{seq 1 100000}}`, "", "exceeds the limit"},
+	} {
+		got, err := execute(fm, tt.text, nil)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: %v", tt.text, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: got error %v, want %q", tt.text, err, tt.wantErr)
+		case got != tt.want:
+			t.Errorf("%s: got %q, want %q", tt.text, got, tt.want)
+		}
+	}
+	if _, ok := fm["toLower"].(func(string) string); !ok {
+		t.Errorf("Guard changed the type of toLower to %T", fm["toLower"])
+	}
+}
+
+func TestMaxStringLen(t *testing.T) {
+	guard := MaxStringLen(3)
+	if err := guard([]interface{}{"abc", []byte("xyz")}); err != nil {
+		t.Errorf("3 bytes: %v", err)
+	}
+	if err := guard([]interface{}{1, "abcd"}); err == nil {
+		t.Error("4 bytes: got nil error")
+	}
+	if err := guard([]interface{}{[]byte("abcd")}); err == nil {
+		t.Error("4 byte slice: got nil error")
+	}
+}
@@ -0,0 +1,148 @@
+package funcmaps
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Handlebars returns functions for templates ported from Handlebars or
+// Mustache: lookup, firstOf and eachMeta, which stands in for the @index,
+// @first and @last data variables of {{#each}}:
+//
+//	{{range eachMeta .Items}}{{if not .First}}, {{end}}{{.Value}}{{end}}
+func Handlebars() FuncMap {
+	return FuncMap{
+		"lookup":   Lookup,
+		"firstOf":  Coalesce,
+		"eachMeta": EachMeta,
+	}
+}
+
+// Lookup returns the element of collection for key: a map value, a slice
+// or array element, or an exported struct field. Unlike index, it returns
+// nil when there is no such element, as Handlebars does.
+func Lookup(collection, key interface{}) interface{} {
+	v, isNil := indirect(reflect.ValueOf(collection))
+	if isNil || !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		k := reflect.ValueOf(key)
+		if !k.IsValid() {
+			return nil
+		}
+		if kt := v.Type().Key(); !k.Type().AssignableTo(kt) {
+			// convert between numeric types, but not numbers to strings
+			if !k.Type().ConvertibleTo(kt) || (k.Kind() == reflect.String) != (kt.Kind() == reflect.String) {
+				return nil
+			}
+			k = k.Convert(kt)
+		}
+		if e := v.MapIndex(k); e.IsValid() {
+			return e.Interface()
+		}
+	case reflect.Slice, reflect.Array:
+		if i, err := indexArg(reflect.ValueOf(key), v.Len()); err == nil {
+			return v.Index(i).Interface()
+		}
+	case reflect.Struct:
+		if name, ok := key.(string); ok {
+			if e, ok := keyValue(v.Interface(), name); ok {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+// indexArg returns the int value of index, if it is in range [0, cap).
+func indexArg(index reflect.Value, cap int) (int, error) {
+	var x int64
+	if !index.IsValid() {
+		return 0, fmt.Errorf("cannot index with nil")
+	}
+	switch index.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x = index.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x = int64(index.Uint())
+	default:
+		return 0, fmt.Errorf("cannot index with type %v", index.Type())
+	}
+	if x < 0 || x >= int64(cap) {
+		return 0, fmt.Errorf("index %d out of range", x)
+	}
+	return int(x), nil
+}
+
+// EachItem is an element of a collection with its position, as returned by
+// EachMeta. Index is 0-based, so Even is true for the first element.
+type EachItem struct {
+	Index int
+	Key   interface{} // map key, or Index
+	Value interface{}
+	First bool
+	Last  bool
+	Odd   bool
+	Even  bool
+}
+
+// EachMeta returns the elements of a slice, array or map with their
+// positions. Map elements are sorted by key.
+func EachMeta(items interface{}) ([]EachItem, error) {
+	v, isNil := indirect(reflect.ValueOf(items))
+	if isNil || !v.IsValid() {
+		return nil, nil
+	}
+	var keys, values []interface{}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			keys = append(keys, i)
+			values = append(values, v.Index(i).Interface())
+		}
+	case reflect.Map:
+		mk := v.MapKeys()
+		sortValues(mk)
+		for _, k := range mk {
+			keys = append(keys, k.Interface())
+			values = append(values, v.MapIndex(k).Interface())
+		}
+	default:
+		return nil, fmt.Errorf("eachMeta: expected slice, array or map, got %s", v.Type())
+	}
+	rs := make([]EachItem, len(values))
+	for i := range rs {
+		rs[i] = EachItem{
+			Index: i,
+			Key:   keys[i],
+			Value: values[i],
+			First: i == 0,
+			Last:  i == len(values)-1,
+			Odd:   i%2 == 1,
+			Even:  i%2 == 0,
+		}
+	}
+	return rs, nil
+}
+
+// sortValues sorts map keys as text/template does when ranging over a map:
+// numbers and strings by value, other keys by their formatted value.
+func sortValues(vs []reflect.Value) {
+	sort.Slice(vs, func(i, j int) bool {
+		a, b := vs[i], vs[j]
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		case reflect.String:
+			return a.String() < b.String()
+		}
+		return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+	})
+}
@@ -0,0 +1,60 @@
+package funcmaps
+
+import (
+	"fmt"
+	"html/template"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// Highlighter renders source code in the language lang as HTML, using the
+// named style or theme.
+//
+// A chroma based implementation only needs to tokenise code with the lexer
+// for lang and format it with an html.Formatter for the style.
+type Highlighter interface {
+	Highlight(lang, code, style string) (string, error)
+}
+
+// PlainHighlighter is a Highlighter that escapes code without highlighting
+// it, marking the language with a "language-" class for client-side
+// highlighters. It is used when Highlight is given a nil Highlighter.
+var PlainHighlighter Highlighter = plainHighlighter{}
+
+// highlightPolicy keeps the markup emitted by syntax highlighters: code
+// containers, spans, classes and simple inline styles.
+var highlightPolicy = func() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements("pre", "code", "span", "div", "table", "tbody", "tr", "td")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^[\w\- ]*$`)).Globally()
+	p.AllowAttrs("style").Matching(regexp.MustCompile(`^(\s*[a-z\-]+\s*:\s*[#\w\s,.%\-]+;?)*\s*$`)).Globally()
+	return p
+}()
+
+// Highlight returns the highlight function, which renders code with h in
+// the given style. The output of h is sanitized before it is returned.
+func Highlight(h Highlighter, style string) FuncMap {
+	if h == nil {
+		h = PlainHighlighter
+	}
+	return FuncMap{
+		"highlight": func(lang string, code interface{}) (template.HTML, error) {
+			out, err := h.Highlight(lang, stringifyValue(code), style)
+			if err != nil {
+				return "", err
+			}
+			return template.HTML(highlightPolicy.Sanitize(out)), nil
+		},
+	}
+}
+
+type plainHighlighter struct{}
+
+func (plainHighlighter) Highlight(lang, code, style string) (string, error) {
+	class := ""
+	if lang != "" {
+		class = fmt.Sprintf(` class="language-%s"`, template.HTMLEscapeString(lang))
+	}
+	return fmt.Sprintf("<pre><code%s>%s</code></pre>", class, template.HTMLEscapeString(code)), nil
+}
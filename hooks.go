@@ -0,0 +1,105 @@
+package funcmaps
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"html"
+	"io"
+	"regexp"
+)
+
+// Hook post-processes the output of a template, such as to minify it,
+// rewrite links or inject a banner.
+type Hook func([]byte) ([]byte, error)
+
+// WithHooks adds hooks applied, in order, to the HTML output of
+// ExecuteTemplate, Render and ExecuteFormats. Output is buffered when hooks
+// are set, and nothing is written if a hook fails.
+func WithHooks(hooks ...Hook) Option {
+	return func(t *Templates) {
+		t.hooks = append(t.hooks, hooks...)
+	}
+}
+
+// ApplyHooks returns b processed by each hook in turn.
+func ApplyHooks(b []byte, hooks ...Hook) ([]byte, error) {
+	for _, hook := range hooks {
+		var err error
+		if b, err = hook(b); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// ExecuteTemplateWith is like ExecuteTemplate, applying hooks after the
+// hooks of t, such as a CSPNonceHook for the nonce of the current request.
+func (t *Templates) ExecuteTemplateWith(w io.Writer, name string, data interface{}, hooks ...Hook) error {
+	return t.execute(w, name, data, hooks)
+}
+
+// output returns the writer executions should write to, and a function
+// writing the output to w once the execution succeeded. Without hooks, the
+// output goes directly to w.
+func (t *Templates) output(w io.Writer, extra []Hook) (io.Writer, func() error) {
+	if len(t.hooks) == 0 && len(extra) == 0 {
+		return w, func() error { return nil }
+	}
+	var buf bytes.Buffer
+	return &buf, func() error {
+		b, err := ApplyHooks(buf.Bytes(), append(t.hooks[:len(t.hooks):len(t.hooks)], extra...)...)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	}
+}
+
+// MinifyHook returns a Hook minifying HTML with m, or with BasicMinifier if
+// m is nil.
+func MinifyHook(m Minifier) Hook {
+	if m == nil {
+		m = BasicMinifier
+	}
+	return func(b []byte) ([]byte, error) {
+		s, err := m.String("text/html", string(b))
+		return []byte(s), err
+	}
+}
+
+// NewNonce returns a random nonce for a Content-Security-Policy header,
+// such as "script-src 'nonce-<nonce>'". Use a new nonce for every response.
+func NewNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+var nonceTagRe = regexp.MustCompile(`(?i)<(script|style)\b[^>]*>`)
+
+var nonceAttrRe = regexp.MustCompile(`(?i)\snonce\s*=`)
+
+// CSPNonceHook returns a Hook adding nonce as the nonce attribute of every
+// script and style element that does not have one.
+func CSPNonceHook(nonce string) Hook {
+	attr := []byte(` nonce="` + html.EscapeString(nonce) + `"`)
+	return func(b []byte) ([]byte, error) {
+		return nonceTagRe.ReplaceAllFunc(b, func(tag []byte) []byte {
+			if nonceAttrRe.Match(tag) {
+				return tag
+			}
+			end := len(tag) - 1
+			if end > 0 && tag[end-1] == '/' {
+				end--
+			}
+			rs := make([]byte, 0, len(tag)+len(attr))
+			rs = append(rs, tag[:end]...)
+			rs = append(rs, attr...)
+			return append(rs, tag[end:]...)
+		}), nil
+	}
+}
@@ -0,0 +1,109 @@
+package funcmaps
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Humanize returns functions writing times and numbers the way people
+// would, as the humanize filters of Django do:
+//
+//	naturalTime .T   => "4 minutes ago", "in 3 days", "now"
+//	naturalDay .T    => "today", "yesterday", "tomorrow" or "Jan 2, 2006"
+//	humanizeInt 3    => "three"
+//
+// Times are as accepted by date; other values, such as nil, give "". The
+// days of naturalDay are those of the location set with
+// WithDefaultLocation, or of the local time zone.
+func Humanize(opts ...MapOption) FuncMap {
+	o := newMapOptions(opts)
+	return o.edit(recordRisks(FuncMap{
+		"naturalTime": func(v interface{}) string {
+			t, ok := toTime(v)
+			if !ok {
+				return ""
+			}
+			return NaturalTime(t, time.Now())
+		},
+		"naturalDay": func(v interface{}) string {
+			t, ok := toTime(v)
+			if !ok {
+				return ""
+			}
+			return NaturalDay(t, time.Now().In(o.location))
+		},
+		"humanizeInt": HumanizeInt,
+	}))
+}
+
+// naturalUnits are the units of NaturalTime, largest first.
+var naturalUnits = []struct {
+	d    time.Duration
+	name string
+}{
+	{365 * 24 * time.Hour, "year"},
+	{30 * 24 * time.Hour, "month"},
+	{7 * 24 * time.Hour, "week"},
+	{24 * time.Hour, "day"},
+	{time.Hour, "hour"},
+	{time.Minute, "minute"},
+	{time.Second, "second"},
+}
+
+// NaturalTime returns the time from now to t in its largest whole unit,
+// such as "4 minutes ago" or "in 3 days", or "now" within a second.
+func NaturalTime(t, now time.Time) string {
+	d := t.Sub(now)
+	future := d > 0
+	if !future {
+		d = -d
+	}
+	for _, u := range naturalUnits {
+		if d < u.d {
+			continue
+		}
+		n := int64(d / u.d)
+		s := fmt.Sprintf("%d %s", n, u.name)
+		if n != 1 {
+			s += "s"
+		}
+		if future {
+			return "in " + s
+		}
+		return s + " ago"
+	}
+	return "now"
+}
+
+// NaturalDay returns "today", "yesterday" or "tomorrow" for t on those days
+// relative to now, in the location of now, or else the date of t, such as
+// "Jan 2, 2006".
+func NaturalDay(t, now time.Time) string {
+	t = t.In(now.Location())
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	y, m, d = now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	switch day.Sub(today) {
+	case 0:
+		return "today"
+	case -24 * time.Hour:
+		return "yesterday"
+	case 24 * time.Hour:
+		return "tomorrow"
+	}
+	return t.Format("Jan 2, 2006")
+}
+
+var numberWords = [...]string{"zero", "one", "two", "three", "four", "five", "six",
+	"seven", "eight", "nine", "ten", "eleven", "twelve"}
+
+// HumanizeInt returns the numbers from one to twelve as words, as in
+// "three items", and other numbers as digits.
+func HumanizeInt(n int) string {
+	if n >= 1 && n < len(numberWords) {
+		return numberWords[n]
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,23 @@
+package funcmaps
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanizeNotTime(t *testing.T) {
+	fm := Humanize()
+	naturalTime := fm["naturalTime"].(func(interface{}) string)
+	naturalDay := fm["naturalDay"].(func(interface{}) string)
+	for _, v := range []interface{}{nil, "yesterday", (*time.Time)(nil)} {
+		if got := naturalTime(v); got != "" {
+			t.Errorf("naturalTime(%#v) = %q, want \"\"", v, got)
+		}
+		if got := naturalDay(v); got != "" {
+			t.Errorf("naturalDay(%#v) = %q, want \"\"", v, got)
+		}
+	}
+	if got := naturalDay(time.Now()); got != "today" {
+		t.Errorf("naturalDay(time.Now()) = %q, want today", got)
+	}
+}
@@ -0,0 +1,144 @@
+package funcmaps
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Icons returns the icon function, inlining SVG icons read from fsys:
+//
+//	{{icon "check" "icon icon-sm"}}          => decorative, hidden from screen readers
+//	{{icon "check" "icon" "Task complete"}}  => labelled image
+//
+// The name is a file name, with ".svg" added when it has no extension.
+// Icons are sanitized by an SVG policy, which keeps only drawing elements
+// and removes scripts, event handlers, styles and links to other
+// documents, so the output is safe even for SVG files from third parties.
+// Sanitized icons are cached.
+func Icons(fsys fs.FS) FuncMap {
+	ic := &iconCache{fsys: fsys}
+	return recordRisks(FuncMap{
+		"icon": ic.icon,
+	})
+}
+
+type iconCache struct {
+	fsys  fs.FS
+	icons sync.Map // name => *html.Node
+}
+
+func (c *iconCache) icon(name, class string, label ...string) (template.HTML, error) {
+	if path.Ext(name) == "" {
+		name += ".svg"
+	}
+	root, err := c.load(name)
+	if err != nil {
+		return "", err
+	}
+	// copy the root element, sharing the sanitized children
+	svg := *root
+	svg.Attr = nil
+	for _, a := range root.Attr {
+		if a.Key != "class" && !strings.HasPrefix(a.Key, "aria-") && a.Key != "role" && a.Key != "focusable" {
+			svg.Attr = append(svg.Attr, a)
+		}
+	}
+	if class != "" {
+		svg.Attr = append(svg.Attr, html.Attribute{Key: "class", Val: class})
+	}
+	if len(label) > 0 && label[0] != "" {
+		svg.Attr = append(svg.Attr, html.Attribute{Key: "role", Val: "img"}, html.Attribute{Key: "aria-label", Val: label[0]})
+	} else {
+		svg.Attr = append(svg.Attr, html.Attribute{Key: "aria-hidden", Val: "true"})
+	}
+	svg.Attr = append(svg.Attr, html.Attribute{Key: "focusable", Val: "false"})
+	var b bytes.Buffer
+	if err := html.Render(&b, &svg); err != nil {
+		return "", err
+	}
+	return template.HTML(b.String()), nil
+}
+
+// load returns the sanitized svg element of the named file.
+func (c *iconCache) load(name string) (*html.Node, error) {
+	if n, ok := c.icons.Load(name); ok {
+		return n.(*html.Node), nil
+	}
+	b, err := fs.ReadFile(c.fsys, fsPath(name))
+	if err != nil {
+		return nil, err
+	}
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(bytes.NewReader(b), body)
+	if err != nil {
+		return nil, fmt.Errorf("icon %s: %v", name, err)
+	}
+	var root *html.Node
+	for _, n := range nodes {
+		if n.Type == html.ElementNode && n.Data == "svg" {
+			root = n
+			break
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("icon %s: no svg element", name)
+	}
+	root.Parent, root.PrevSibling, root.NextSibling = nil, nil, nil
+	sanitizeSVG(root)
+	c.icons.Store(name, root)
+	return root, nil
+}
+
+// svgElements are the elements kept by sanitizeSVG, as named by the HTML
+// parser.
+var svgElements = map[string]bool{
+	"svg": true, "g": true, "path": true, "circle": true, "ellipse": true,
+	"line": true, "polyline": true, "polygon": true, "rect": true,
+	"text": true, "tspan": true, "title": true, "desc": true, "defs": true,
+	"use": true, "symbol": true, "linearGradient": true, "radialGradient": true,
+	"stop": true, "clipPath": true, "mask": true, "pattern": true,
+}
+
+// externalURLRe matches CSS url() references to other documents.
+var externalURLRe = regexp.MustCompile(`(?i)url\(\s*['"]?[^#'"\s)]`)
+
+// sanitizeSVG removes the descendants of n that are not drawing elements,
+// and the attributes that run scripts, apply styles or load other
+// documents.
+func sanitizeSVG(n *html.Node) {
+	attrs := n.Attr[:0]
+	for _, a := range n.Attr {
+		key := strings.ToLower(a.Key)
+		switch {
+		case strings.HasPrefix(key, "on"), key == "style":
+		case key == "href" || a.Namespace == "xlink" && key == "href":
+			if strings.HasPrefix(a.Val, "#") {
+				attrs = append(attrs, a)
+			}
+		case strings.Contains(strings.ToLower(a.Val), "javascript:"), externalURLRe.MatchString(a.Val):
+		default:
+			attrs = append(attrs, a)
+		}
+	}
+	n.Attr = attrs
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch {
+		case c.Type == html.ElementNode && svgElements[c.Data]:
+			sanitizeSVG(c)
+		case c.Type == html.TextNode:
+		default:
+			n.RemoveChild(c)
+		}
+		c = next
+	}
+}
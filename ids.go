@@ -0,0 +1,196 @@
+package funcmaps
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IDOption configures the generators returned by IDs.
+type IDOption func(*idGenerator)
+
+// WithRandom makes the ID generators read randomness from r instead of
+// crypto/rand, for reproducible output in tests. r need not be safe for
+// concurrent use.
+func WithRandom(r io.Reader) IDOption {
+	return func(g *idGenerator) {
+		g.rand = r
+	}
+}
+
+// WithClock makes the time-based ID generators use now for the current time.
+func WithClock(now func() time.Time) IDOption {
+	return func(g *idGenerator) {
+		g.now = now
+	}
+}
+
+// IDs returns functions generating unique identifiers:
+//
+//	uuidv4                => random UUID
+//	uuidv5 "dns" "a.com"  => UUID from a namespace and name
+//	uuidv7                => time-ordered UUID
+//	ulid                  => time-ordered 26 character ULID
+//	nanoid 21             => URL-safe random ID of the given length
+//	shortid               => 10 character alphanumeric random ID
+func IDs(opts ...IDOption) FuncMap {
+	g := &idGenerator{rand: rand.Reader, now: time.Now}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return recordRisks(FuncMap{
+		"uuidv4":  g.uuidv4,
+		"uuidv5":  UUIDv5,
+		"uuidv7":  g.uuidv7,
+		"ulid":    g.ulid,
+		"nanoid":  g.nanoid,
+		"shortid": g.shortid,
+	})
+}
+
+// ElementIDs is a StateFuncs adding uniqueID, which returns "prefix-1",
+// "prefix-2" and so on within one execution, so that partials rendered more
+// than once give their elements distinct ids for label and aria attributes:
+//
+//	t := funcmaps.New(fm, funcmaps.WithState(funcmaps.ElementIDs))
+//
+//	{{$id := uniqueID "email"}}
+//	<label for="{{$id}}">Email</label><input id="{{$id}}" type="email">
+func ElementIDs(st *ExecState) FuncMap {
+	return FuncMap{
+		"uniqueID": func(prefix string) string {
+			if prefix == "" {
+				prefix = "id"
+			}
+			n := st.Update("funcmaps.uniqueID."+prefix, func(v interface{}, ok bool) interface{} {
+				n, _ := v.(int)
+				return n + 1
+			})
+			return fmt.Sprintf("%s-%d", prefix, n)
+		},
+	}
+}
+
+// UUIDv5 returns the name-based UUID of name in namespace. The namespace is
+// "dns", "url", "oid", "x500" or a UUID.
+func UUIDv5(namespace, name string) (string, error) {
+	var ns uuid.UUID
+	switch strings.ToLower(namespace) {
+	case "dns":
+		ns = uuid.NameSpaceDNS
+	case "url":
+		ns = uuid.NameSpaceURL
+	case "oid":
+		ns = uuid.NameSpaceOID
+	case "x500":
+		ns = uuid.NameSpaceX500
+	default:
+		var err error
+		if ns, err = uuid.Parse(namespace); err != nil {
+			return "", fmt.Errorf("uuidv5: invalid namespace %q", namespace)
+		}
+	}
+	return uuid.NewSHA1(ns, []byte(name)).String(), nil
+}
+
+type idGenerator struct {
+	mu   sync.Mutex // guards rand
+	rand io.Reader
+	now  func() time.Time
+}
+
+func (g *idGenerator) read(b []byte) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, err := io.ReadFull(g.rand, b)
+	return err
+}
+
+func (g *idGenerator) uuidv4() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	u, err := uuid.NewRandomFromReader(g.rand)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (g *idGenerator) uuidv7() (string, error) {
+	var u uuid.UUID
+	if err := g.read(u[6:]); err != nil {
+		return "", err
+	}
+	ms := uint64(g.now().UnixNano() / int64(time.Millisecond))
+	u[0], u[1], u[2] = byte(ms>>40), byte(ms>>32), byte(ms>>24)
+	u[3], u[4], u[5] = byte(ms>>16), byte(ms>>8), byte(ms)
+	u[6] = u[6]&0x0f | 0x70 // version 7
+	u[8] = u[8]&0x3f | 0x80 // RFC 4122 variant
+	return u.String(), nil
+}
+
+// crockford is the base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+func (g *idGenerator) ulid() (string, error) {
+	var b [16]byte
+	ms := uint64(g.now().UnixNano() / int64(time.Millisecond))
+	binary.BigEndian.PutUint16(b[0:], uint16(ms>>32))
+	binary.BigEndian.PutUint32(b[2:], uint32(ms))
+	if err := g.read(b[6:]); err != nil {
+		return "", err
+	}
+	// 128 bits as 26 base32 digits, the first holding only 3 bits
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out), nil
+}
+
+// nanoidAlphabet has 64 characters, so each random byte maps to one
+// character without bias.
+const nanoidAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+func (g *idGenerator) nanoid(n int) (string, error) {
+	if n <= 0 {
+		return "", fmt.Errorf("nanoid: invalid length %d", n)
+	}
+	b := make([]byte, n)
+	if err := g.read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = nanoidAlphabet[b[i]&63]
+	}
+	return string(b), nil
+}
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+func (g *idGenerator) shortid() (string, error) {
+	out := make([]byte, 0, 10)
+	buf := make([]byte, 16)
+	for len(out) < cap(out) {
+		if err := g.read(buf); err != nil {
+			return "", err
+		}
+		for _, c := range buf {
+			// reject values that would make some characters more likely
+			if c < 248 && len(out) < cap(out) {
+				out = append(out, base62[c%62])
+			}
+		}
+	}
+	return string(out), nil
+}
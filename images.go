@@ -0,0 +1,160 @@
+package funcmaps
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"image"
+	_ "image/gif"  // register GIF decoding for ImageDimensions
+	_ "image/jpeg" // register JPEG decoding for ImageDimensions
+	_ "image/png"  // register PNG decoding for ImageDimensions
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// Images returns functions inspecting and inlining images read from fsys.
+// Paths are relative to the root of fsys; a leading slash is ignored.
+func Images(fsys fs.FS) FuncMap {
+	return recordRisks(FuncMap{
+		"imageDims": func(name string) (ImageDims, error) { return ImageDimensions(fsys, name) },
+		"dataURI":   func(name string) (template.URL, error) { return DataURI(fsys, name) },
+		"srcset": func(name string, widths ...interface{}) (string, error) {
+			ws, err := intList(widths)
+			return Srcset(name, ws...), err
+		},
+		"picture": func(name, sizes, alt string, widths ...interface{}) (template.HTML, error) {
+			ws, err := intList(widths)
+			if err != nil {
+				return "", err
+			}
+			return Picture(fsys, name, sizes, alt, ws...), nil
+		},
+	})
+}
+
+// ImageDims holds the size of an image in pixels.
+type ImageDims struct {
+	Width  int
+	Height int
+}
+
+// ImageDimensions returns the width and height of a GIF, JPEG or PNG image,
+// without decoding the whole image.
+func ImageDimensions(fsys fs.FS, name string) (ImageDims, error) {
+	f, err := fsys.Open(fsPath(name))
+	if err != nil {
+		return ImageDims{}, err
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return ImageDims{}, fmt.Errorf("%s: %v", name, err)
+	}
+	return ImageDims{Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// DataURI returns the contents of the named file as a base64 data URI.
+// The media type is guessed from the file extension, then from the content.
+func DataURI(fsys fs.FS, name string) (template.URL, error) {
+	b, err := fs.ReadFile(fsys, fsPath(name))
+	if err != nil {
+		return "", err
+	}
+	typ := mime.TypeByExtension(path.Ext(name))
+	if typ == "" {
+		typ = http.DetectContentType(b)
+	}
+	return template.URL("data:" + typ + ";base64," + base64.StdEncoding.EncodeToString(b)), nil
+}
+
+// Srcset returns a srcset attribute value for the given widths, naming each
+// candidate by inserting "-<width>" before the extension of name:
+//
+//	srcset "img/hero.jpg" 320 640 => "img/hero-320.jpg 320w, img/hero-640.jpg 640w"
+func Srcset(name string, widths ...int) string {
+	rs := make([]string, 0, len(widths))
+	for _, w := range widths {
+		rs = append(rs, imageVariant(name, w)+" "+strconv.Itoa(w)+"w")
+	}
+	return strings.Join(rs, ", ")
+}
+
+// imageVariant returns the name of the image name resized to width.
+func imageVariant(name string, width int) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-" + strconv.Itoa(width) + ext
+}
+
+// Picture returns a picture element for the image name in the given widths,
+// named as by Srcset, with sizes as the sizes attribute, such as
+// "(max-width: 600px) 100vw, 50vw":
+//
+//	{{picture "img/hero.jpg" "100vw" "A hero" 320 640 1280}}
+//
+// If fsys is not nil, the img element gets the width and height of the
+// image name, so that browsers reserve its space before loading it, and a
+// WebP source is added if fsys has the WebP variant of the first width.
+func Picture(fsys fs.FS, name, sizes, alt string, widths ...int) template.HTML {
+	esc := template.HTMLEscapeString
+	var b strings.Builder
+	b.WriteString("<picture>")
+	if fsys != nil && len(widths) > 0 {
+		webp := strings.TrimSuffix(name, path.Ext(name)) + ".webp"
+		if _, err := fs.Stat(fsys, fsPath(imageVariant(webp, widths[0]))); err == nil {
+			fmt.Fprintf(&b, `<source type="image/webp" srcset="%s" sizes="%s">`, esc(Srcset(webp, widths...)), esc(sizes))
+		}
+	}
+	fmt.Fprintf(&b, `<img src="%s"`, esc(name))
+	if len(widths) > 0 {
+		fmt.Fprintf(&b, ` srcset="%s" sizes="%s"`, esc(Srcset(name, widths...)), esc(sizes))
+	}
+	fmt.Fprintf(&b, ` alt="%s"`, esc(alt))
+	if fsys != nil {
+		if dims, err := ImageDimensions(fsys, name); err == nil {
+			fmt.Fprintf(&b, ` width="%d" height="%d"`, dims.Width, dims.Height)
+		}
+	}
+	b.WriteString(` loading="lazy"></picture>`)
+	return template.HTML(b.String())
+}
+
+// intList returns the ints of values, each an int or a list of ints, as
+// given to a variadic template function.
+func intList(values []interface{}) ([]int, error) {
+	var rs []int
+	for _, v := range values {
+		if isList(v) {
+			list, err := listValues(v)
+			if err != nil {
+				return nil, err
+			}
+			sub, err := intList(list)
+			if err != nil {
+				return nil, err
+			}
+			rs = append(rs, sub...)
+			continue
+		}
+		n, err := cast.ToIntE(v)
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, n)
+	}
+	return rs, nil
+}
+
+// fsPath converts a URL-style path into a name accepted by fs.FS.
+func fsPath(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
@@ -0,0 +1,83 @@
+package funcmaps
+
+import (
+	"log"
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+// FuncCall describes a call of a template function, as logged by
+// Instrument.
+type FuncCall struct {
+	Name     string
+	Duration time.Duration
+	Err      error // the error returned by the function, or its panic
+}
+
+// Logger receives the function calls logged by Instrument. It must be safe
+// for concurrent use.
+type Logger interface {
+	LogCall(c FuncCall)
+}
+
+// LoggerFunc adapts a function to a Logger.
+type LoggerFunc func(c FuncCall)
+
+// LogCall calls f(c).
+func (f LoggerFunc) LogCall(c FuncCall) { f(c) }
+
+// StdLogger returns a Logger printing calls to l as
+// "funcmaps: sanitize 1.2ms error: ...".
+func StdLogger(l *log.Logger) Logger {
+	return LoggerFunc(func(c FuncCall) {
+		if c.Err != nil {
+			l.Printf("funcmaps: %s %v error: %v", c.Name, c.Duration, c.Err)
+			return
+		}
+		l.Printf("funcmaps: %s %v", c.Name, c.Duration)
+	})
+}
+
+// InstrumentOption configures Instrument.
+type InstrumentOption func(*instrumentOptions)
+
+type instrumentOptions struct {
+	rate float64       // fraction of successful calls logged
+	slow time.Duration // calls at least this long are always logged, if > 0
+}
+
+// SampleRate makes Instrument log only the given fraction, between 0 and
+// 1, of the calls that succeed, for busy servers. Failed calls, and slow
+// ones, are always logged.
+func SampleRate(rate float64) InstrumentOption {
+	return func(o *instrumentOptions) {
+		o.rate = rate
+	}
+}
+
+// SlowCalls makes Instrument always log calls taking at least d, whatever
+// the sample rate.
+func SlowCalls(d time.Duration) InstrumentOption {
+	return func(o *instrumentOptions) {
+		o.slow = d
+	}
+}
+
+// Instrument returns a copy of fm whose functions log their name, duration
+// and error to logger, so that operators can find slow or failing template
+// functions:
+//
+//	fm = funcmaps.Instrument(fm, funcmaps.StdLogger(log.Default()),
+//		funcmaps.SampleRate(0.01), funcmaps.SlowCalls(10*time.Millisecond))
+func Instrument(fm FuncMap, logger Logger, opts ...InstrumentOption) FuncMap {
+	o := &instrumentOptions{rate: 1}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return wrapFuncs(fm, observeCall(func(c FuncCall, _, _ []reflect.Value) {
+		if c.Err != nil || o.slow > 0 && c.Duration >= o.slow || o.rate >= 1 || rand.Float64() < o.rate {
+			logger.LogCall(c)
+		}
+	}))
+}
@@ -0,0 +1,264 @@
+//go:build go1.23
+
+package funcmaps
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"iter"
+	"reflect"
+)
+
+// maxLineLength is the length of the longest line yielded by seqLines.
+const maxLineLength = 1 << 20
+
+// Iterators returns functions building lazy sequences, so that large data
+// sets can be ranged over without building intermediate slices. The
+// sequences are iter.Seq values, which templates range over since Go 1.24:
+//
+//	{{ range seqLines "access.log" | seqGrep "ERROR" | seqTake 100 }}
+//
+// seqLines reads the files of fsys, as SeqLinesErr does, so that a range
+// declaring two variables gets the errors reading them:
+//
+//	{{ range $line, $err := seqLines "access.log" }}
+//
+// The other functions accept sequences, slices, arrays and maps, and end
+// the sequences of seqLines at their first error.
+func Iterators(fsys fs.FS) FuncMap {
+	return recordRisks(FuncMap{
+		"seqLines":   func(name string) (iter.Seq2[interface{}, error], error) { return templateLines(fsys, name) },
+		"seqOf":      SeqOf,
+		"seqGrep":    SeqGrep,
+		"seqWhere":   SeqWhere,
+		"seqMap":     SeqMap,
+		"seqTake":    SeqTake,
+		"seqSkip":    SeqSkip,
+		"seqCollect": SeqCollect,
+	})
+}
+
+// SeqLines returns the lines of the file name of fsys, without their line
+// endings. The file is opened when the sequence is ranged over, and read
+// until the end or the range stops; an error opening or reading the file
+// also ends the sequence. Use SeqLinesErr to get the error.
+func SeqLines(fsys fs.FS, name string) (iter.Seq[interface{}], error) {
+	lines, err := SeqLinesErr(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(interface{}) bool) {
+		for line, err := range lines {
+			if err != nil || !yield(line) {
+				return
+			}
+		}
+	}, nil
+}
+
+// SeqLinesErr is like SeqLines, yielding an error opening or reading the
+// file, such as a line longer than 1 MiB, last, with an empty line.
+func SeqLinesErr(fsys fs.FS, name string) (iter.Seq2[string, error], error) {
+	if _, err := fs.Stat(fsys, name); err != nil {
+		return nil, err
+	}
+	return func(yield func(string, error) bool) {
+		f, err := fsys.Open(name)
+		if err != nil {
+			yield("", err)
+			return
+		}
+		defer f.Close()
+		sc := bufio.NewScanner(f)
+		sc.Buffer(nil, maxLineLength)
+		for sc.Scan() {
+			if !yield(sc.Text(), nil) {
+				return
+			}
+		}
+		if err := sc.Err(); err != nil {
+			yield("", fmt.Errorf("%s: %w", name, err))
+		}
+	}, nil
+}
+
+// templateLines returns the lines of SeqLinesErr as a sequence the other
+// functions accept.
+func templateLines(fsys fs.FS, name string) (iter.Seq2[interface{}, error], error) {
+	lines, err := SeqLinesErr(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(interface{}, error) bool) {
+		for line, err := range lines {
+			if !yield(line, err) {
+				return
+			}
+		}
+	}, nil
+}
+
+// SeqOf returns a sequence of the values of v, a sequence, slice, array or
+// map. The sequences of values and errors, such as of seqLines, end at
+// their first error.
+func SeqOf(v interface{}) (iter.Seq[interface{}], error) {
+	switch v := v.(type) {
+	case iter.Seq[interface{}]:
+		return v, nil
+	case iter.Seq2[interface{}, error]:
+		return func(yield func(interface{}) bool) {
+			for v, err := range v {
+				if err != nil || !yield(v) {
+					return
+				}
+			}
+		}, nil
+	case iter.Seq[string]:
+		return func(yield func(interface{}) bool) {
+			for s := range v {
+				if !yield(s) {
+					return
+				}
+			}
+		}, nil
+	}
+	rv, isNil := indirect(reflect.ValueOf(v))
+	if isNil || !rv.IsValid() {
+		return func(func(interface{}) bool) {}, nil
+	}
+	switch rv.Kind() {
+	case reflect.Array, reflect.Slice:
+		return func(yield func(interface{}) bool) {
+			for i := 0; i < rv.Len(); i++ {
+				if !yield(rv.Index(i).Interface()) {
+					return
+				}
+			}
+		}, nil
+	case reflect.Map:
+		return func(yield func(interface{}) bool) {
+			r := rv.MapRange()
+			for r.Next() {
+				if !yield(r.Value().Interface()) {
+					return
+				}
+			}
+		}, nil
+	}
+	return nil, fmt.Errorf("expected sequence, slice, array or map, got %s", rv.Type())
+}
+
+var seqGrepCache = newRegexpCache(regexpCacheSize)
+
+// SeqGrep returns the values of seq whose text matches the regular
+// expression pattern.
+func SeqGrep(pattern string, seq interface{}) (iter.Seq[interface{}], error) {
+	re, err := seqGrepCache.compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return seqFilter(seq, func(v interface{}) bool {
+		s, err := toText(v)
+		return err == nil && re.MatchString(s)
+	})
+}
+
+// SeqWhere returns the values of seq whose map key or struct field key
+// equals value.
+func SeqWhere(key string, value interface{}, seq interface{}) (iter.Seq[interface{}], error) {
+	want := reflect.ValueOf(value)
+	return seqFilter(seq, func(v interface{}) bool {
+		got, ok := keyValue(v, key)
+		if !ok {
+			return false
+		}
+		same, err := eq(reflect.ValueOf(got), want)
+		return err == nil && same
+	})
+}
+
+func seqFilter(seq interface{}, keep func(interface{}) bool) (iter.Seq[interface{}], error) {
+	src, err := SeqOf(seq)
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(interface{}) bool) {
+		for v := range src {
+			if keep(v) && !yield(v) {
+				return
+			}
+		}
+	}, nil
+}
+
+// SeqMap returns the map key or struct field key of each value of seq,
+// skipping values without it.
+func SeqMap(key string, seq interface{}) (iter.Seq[interface{}], error) {
+	src, err := SeqOf(seq)
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(interface{}) bool) {
+		for v := range src {
+			if f, ok := keyValue(v, key); ok && !yield(f) {
+				return
+			}
+		}
+	}, nil
+}
+
+// SeqTake returns the first n values of seq.
+func SeqTake(n int, seq interface{}) (iter.Seq[interface{}], error) {
+	src, err := SeqOf(seq)
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(interface{}) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for v := range src {
+			if !yield(v) {
+				return
+			}
+			if i++; i >= n {
+				return
+			}
+		}
+	}, nil
+}
+
+// SeqSkip returns the values of seq after the first n.
+func SeqSkip(n int, seq interface{}) (iter.Seq[interface{}], error) {
+	src, err := SeqOf(seq)
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(interface{}) bool) {
+		i := 0
+		for v := range src {
+			if i++; i <= n {
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}, nil
+}
+
+// SeqCollect returns the values of seq as a slice, for functions needing
+// one.
+func SeqCollect(seq interface{}) ([]interface{}, error) {
+	src, err := SeqOf(seq)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]interface{}, 0)
+	for v := range src {
+		rs = append(rs, v)
+	}
+	return rs, nil
+}
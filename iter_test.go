@@ -0,0 +1,60 @@
+//go:build go1.23
+
+package funcmaps
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"text/template"
+)
+
+func TestSeqLinesErr(t *testing.T) {
+	fsys := fstest.MapFS{"long.txt": {Data: []byte("a\n" + strings.Repeat("x", maxLineLength+1) + "\nb\n")}}
+	lines, err := SeqLinesErr(fsys, "long.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	var last error
+	for line, err := range lines {
+		got = append(got, line)
+		last = err
+	}
+	if len(got) != 2 || got[0] != "a" || last == nil {
+		t.Errorf("got lines %q and error %v, want a and a line too long error", got, last)
+	}
+
+	plain, err := SeqLines(fsys, "long.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for range plain {
+		n++
+	}
+	if n != 1 {
+		t.Errorf("SeqLines yielded %d lines, want 1", n)
+	}
+}
+
+func TestSeqLinesTemplate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"log.txt":  {Data: []byte("ok 1\nERROR 2\nok 3\nERROR 4\n")},
+		"long.txt": {Data: []byte("a\n" + strings.Repeat("x", maxLineLength+1) + "\n")},
+	}
+	for _, tt := range []struct{ text, want string }{
+		{`{{range seqLines "log.txt" | seqGrep "ERROR" | seqTake 1}}{{.}};{{end}}`, "ERROR 2;"},
+		{`{{range seqLines "log.txt"}}{{.}};{{end}}`, "ok 1;ERROR 2;ok 3;ERROR 4;"},
+		{`{{range $line, $err := seqLines "long.txt"}}{{if $err}}error{{else}}{{$line}};{{end}}{{end}}`, "a;error"},
+	} {
+		var buf bytes.Buffer
+		tmpl := template.Must(template.New("").Funcs(template.FuncMap(Iterators(fsys))).Parse(tt.text))
+		if err := tmpl.Execute(&buf, nil); err != nil {
+			t.Errorf("%s: %v", tt.text, err)
+		} else if buf.String() != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.text, buf.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package funcmaps
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// K8s returns functions for writing Kubernetes manifests and other YAML
+// without Helm.
+func K8s() FuncMap {
+	return FuncMap{
+		"indentYaml":     IndentYAML,
+		"toYamlPretty":   ToYAML,
+		"b64secret":      B64Secret,
+		"quoteAll":       QuoteAll,
+		"parseQuantity":  ParseQuantity,
+		"formatQuantity": FormatQuantity,
+		"deepMerge": func(maps ...interface{}) (map[string]interface{}, error) {
+			return DeepMerge(MergePolicy{}, maps...)
+		},
+		"deepMergeWithPolicy": DeepMergeWithPolicy,
+	}
+}
+
+// IndentYAML indents every non-empty line of s by n spaces, for nesting a
+// block of YAML:
+//
+//	spec:
+//	{{ toYamlPretty .Spec | indentYaml 2 }}
+func IndentYAML(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ToYAML returns v marshaled as YAML, without a trailing newline.
+func ToYAML(v interface{}) (string, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+var secretKeyRe = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// B64Secret returns the entries of the map data as the data block of a
+// Kubernetes Secret, each value base64 encoded, in order of key:
+//
+//	data:
+//	{{ b64secret .Secrets | indentYaml 2 }}
+func B64Secret(data interface{}) (string, error) {
+	v, isNil := indirect(reflect.ValueOf(data))
+	if isNil || !v.IsValid() {
+		return "", nil
+	}
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return "", fmt.Errorf("b64secret: expected map with string keys, got %s", v.Type())
+	}
+	lines := make([]string, 0, v.Len())
+	r := v.MapRange()
+	for r.Next() {
+		key := r.Key().String()
+		if !secretKeyRe.MatchString(key) {
+			return "", fmt.Errorf("b64secret: invalid key %q", key)
+		}
+		var b []byte
+		if raw, ok := r.Value().Interface().([]byte); ok {
+			b = raw
+		} else {
+			s, err := toText(r.Value().Interface())
+			if err != nil {
+				return "", err
+			}
+			b = []byte(s)
+		}
+		lines = append(lines, key+": "+base64.StdEncoding.EncodeToString(b))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n"), nil
+}
+
+// QuoteAll returns each value of list as a double quoted string, safe to
+// use as a YAML scalar.
+func QuoteAll(list interface{}) ([]string, error) {
+	values, err := listValues(list)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]string, len(values))
+	for i, v := range values {
+		s, err := toText(v)
+		if err != nil {
+			return nil, err
+		}
+		rs[i] = strconv.Quote(s)
+	}
+	return rs, nil
+}
+
+// quantitySuffixes are the multipliers of the Kubernetes quantity suffixes.
+var quantitySuffixes = map[string]float64{
+	"n": 1e-9, "u": 1e-6, "m": 1e-3, "": 1,
+	"k": 1e3, "M": 1e6, "G": 1e9, "T": 1e12, "P": 1e15, "E": 1e18,
+	"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40, "Pi": 1 << 50, "Ei": 1 << 60,
+}
+
+var quantityRe = regexp.MustCompile(`^([+-]?(?:\d+\.?\d*|\.\d+)(?:[eE][+-]?\d+)?)([a-zA-Z]*)$`)
+
+// ParseQuantity parses a Kubernetes resource quantity, such as "500m" CPU
+// or "1.5Gi" of memory, returning its value: 0.5 and 1610612736.
+func ParseQuantity(s string) (float64, error) {
+	m := quantityRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+	mult, ok := quantitySuffixes[m[2]]
+	if !ok {
+		return 0, fmt.Errorf("invalid quantity suffix in %q", s)
+	}
+	f, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+	return f * mult, nil
+}
+
+// FormatQuantity formats v as a Kubernetes resource quantity: whole
+// multiples of powers of 1024 use the binary suffixes, such as "512Mi",
+// other whole numbers are written as they are, and fractions in
+// thousandths, such as "250m".
+func FormatQuantity(v float64) (string, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "", fmt.Errorf("invalid quantity %v", v)
+	}
+	if v != math.Trunc(v) {
+		return strconv.FormatFloat(math.Round(v*1000), 'f', -1, 64) + "m", nil
+	}
+	for _, suffix := range []string{"Ei", "Pi", "Ti", "Gi", "Mi", "Ki"} {
+		mult := quantitySuffixes[suffix]
+		if v != 0 && math.Abs(v) >= mult && math.Mod(v, mult) == 0 {
+			return strconv.FormatFloat(v/mult, 'f', -1, 64) + suffix, nil
+		}
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64), nil
+}
@@ -0,0 +1,118 @@
+package funcmaps
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"io"
+	ttemplate "text/template"
+)
+
+// WithLayout sets the layout template used by Render for pages that do not
+// choose one with the layout function.
+func WithLayout(name string) Option {
+	return func(t *Templates) {
+		t.layout = name
+	}
+}
+
+var errNoContent = errors.New("content is only available in layouts executed by Render")
+
+// renderFuncs returns the per-execution functions of Templates, in the
+// state they have outside of Render, bound to the executing clone c.
+func renderFuncs(c *template.Template, cache *Cache) template.FuncMap {
+	return template.FuncMap{
+		"layout":        func(string) string { return "" },
+		"content":       func() (template.HTML, error) { return "", errNoContent },
+		"yield":         func(string, ...interface{}) (template.HTML, error) { return "", nil },
+		"partial":       partialFunc(c),
+		"cachedPartial": cachedPartialFunc(cache, partialFunc(c)),
+	}
+}
+
+// textRenderFuncs is renderFuncs for text templates, bound to the
+// executing clone c of the text templates.
+func textRenderFuncs(c *ttemplate.Template, cache *Cache) ttemplate.FuncMap {
+	partial := textPartialFunc(c)
+	cached := cachedPartialFunc(cache, func(name string, data ...interface{}) (template.HTML, error) {
+		s, err := partial(name, data...)
+		return template.HTML(s), err
+	})
+	return ttemplate.FuncMap{
+		"layout":  func(string) string { return "" },
+		"content": func() (string, error) { return "", errNoContent },
+		"yield":   func(string, ...interface{}) (string, error) { return "", nil },
+		"partial": partial,
+		"cachedPartial": func(key string, ttl interface{}, name string, data ...interface{}) (string, error) {
+			s, err := cached(key, ttl, name, data...)
+			return string(s), err
+		},
+	}
+}
+
+// Render executes the template page with data and then wraps the output in
+// a layout template.
+//
+// The layout is the one given to WithLayout, unless the page chooses another
+// with {{layout "name"}}, or none with {{layout ""}}. Within the layout,
+// {{content}} inserts the output of the page, and {{yield "name" .}}
+// executes the page's "<page>:name" template, falling back to the layout's
+// "<layout>:name" template for default content, or nothing:
+//
+//	{{/* about.html */}}
+//	{{layout "wide.html"}}<p>About us</p>
+//	{{define "about.html:title"}}About{{end}}
+//
+//	{{/* wide.html */}}
+//	<title>{{yield "title" .}}</title><main>{{content}}</main>
+//	{{define "wide.html:title"}}Example Site{{end}}
+func (t *Templates) Render(w io.Writer, page string, data interface{}) error {
+	set, err := t.current()
+	if err != nil {
+		return err
+	}
+	layout := t.layout
+	var body bytes.Buffer
+	out, flush := t.output(w, nil)
+	err = set.with(nil, page, nil, func(c *template.Template) error {
+		yield := func(name string, args ...interface{}) (template.HTML, error) {
+			var arg interface{}
+			if len(args) > 0 {
+				arg = args[0]
+			}
+			for _, full := range []string{page + ":" + name, layout + ":" + name} {
+				if c.Lookup(full) == nil {
+					continue
+				}
+				var b bytes.Buffer
+				if err := c.ExecuteTemplate(&b, full, arg); err != nil {
+					return "", err
+				}
+				return template.HTML(b.String()), nil
+			}
+			return "", nil
+		}
+		c.Funcs(template.FuncMap{
+			"layout": func(name string) string {
+				layout = name
+				return ""
+			},
+			"yield": yield,
+		})
+		if err := c.ExecuteTemplate(&body, page, data); err != nil {
+			return err
+		}
+		if layout == "" {
+			_, err := out.Write(body.Bytes())
+			return err
+		}
+		c.Funcs(template.FuncMap{
+			"content": func() template.HTML { return template.HTML(body.String()) },
+		})
+		return c.ExecuteTemplate(out, layout, data)
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
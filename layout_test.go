@@ -0,0 +1,69 @@
+package funcmaps
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var layoutFS = fstest.MapFS{
+	"base.html":  {Data: []byte(`<title>{{yield "title" .}}</title><main>{{content}}</main>{{define "base.html:title"}}Site{{end}}`)},
+	"wide.html":  {Data: []byte(`<div class="wide">{{content}}</div>`)},
+	"index.html": {Data: []byte(`<p>{{.}}</p>`)},
+	"about.html": {Data: []byte(`{{layout "wide.html"}}<p>About</p>`)},
+	"raw.html":   {Data: []byte(`{{layout ""}}raw`)},
+	"title.html": {Data: []byte(`<p>{{.}}</p>{{define "title.html:title"}}{{.}} page{{end}}`)},
+	"fail.html":  {Data: []byte(`{{mustToInt .}}`)},
+}
+
+func TestRender(t *testing.T) {
+	ts := New(Combined(Default(), Convert()), WithLayout("base.html"))
+	if err := ts.ParseFS(layoutFS, "*.html"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		page string
+		want string
+	}{
+		{"index.html", `<title>Site</title><main><p>&lt;b&gt;</p></main>`},
+		{"about.html", `<div class="wide"><p>About</p></div>`},
+		{"raw.html", `raw`},
+		{"title.html", `<title>&lt;b&gt; page</title><main><p>&lt;b&gt;</p></main>`},
+	} {
+		var buf bytes.Buffer
+		if err := ts.Render(&buf, tt.page, "<b>"); err != nil {
+			t.Errorf("%s: %v", tt.page, err)
+		} else if buf.String() != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.page, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestRenderError(t *testing.T) {
+	ts := New(Combined(Default(), Convert()), WithLayout("base.html"))
+	if err := ts.ParseFS(layoutFS, "*.html"); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err := ts.Render(&buf, "fail.html", "x")
+	var re *RenderError
+	if !errors.As(err, &re) || re.Template != "fail.html" || re.Func != "mustToInt" {
+		t.Errorf("got error %#v, want a *RenderError of mustToInt in fail.html", err)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("wrote %q on error", buf.String())
+	}
+	if err := ts.Render(&buf, "missing.html", nil); err == nil {
+		t.Error("Render of a missing page: got nil error")
+	}
+}
+
+func TestTemplatesParseError(t *testing.T) {
+	ts := New(Default())
+	err := ts.ParseFS(fstest.MapFS{"a.html": {Data: []byte(`{{toUppr .}}`)}}, "*.html")
+	if err == nil || !strings.Contains(err.Error(), `did you mean "toUpper"`) {
+		t.Errorf("got error %v, want a suggestion of toUpper", err)
+	}
+}
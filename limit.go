@@ -0,0 +1,131 @@
+package funcmaps
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// ErrLimitExceeded is the error of function calls refused or stopped by
+// Limit.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// LimitOptions are the resource limits enforced by Limit. Zero values
+// mean no limit.
+type LimitOptions struct {
+	// MaxOutputBytes limits the size of string, byte slice and
+	// html/template content results of each call. Results are checked
+	// once returned, except those of repeat, repeat_n and seq, whose size
+	// is known from their arguments and which are refused before
+	// allocating. The integers of seq count as 8 bytes each.
+	MaxOutputBytes int
+	// Timeout limits the duration of each call. The goroutine of a call
+	// running longer keeps running until the function returns.
+	Timeout time.Duration
+	// MaxCalls limits the number of calls of all the functions together.
+	MaxCalls int64
+}
+
+// outputGuards return the ArgGuards refusing the calls of the functions of
+// this package whose results would exceed max bytes, used by Limit.
+var outputGuards = map[string]func(max int) ArgGuard{
+	"repeat":   MaxRepeatLen,
+	"repeat_n": MaxRepeatLen,
+	"seq":      func(max int) ArgGuard { return MaxSeqLen(max / 8) },
+}
+
+// Limit returns a copy of fm whose functions fail with ErrLimitExceeded
+// beyond the limits of opts, protecting servers rendering untrusted
+// templates from output bombs such as {{repeat 1000000000 "x"}} and from
+// runaway loops.
+//
+// Output sizes are checked before the call only for repeat, repeat_n and
+// seq, found by name. The results of other functions are
+// checked after the call, once allocated, so their arguments should also
+// be limited with Guard and DefaultGuards.
+//
+// A function still running at its timeout is abandoned, not stopped: the
+// call fails, but the function runs to completion in the background.
+//
+// MaxCalls counts the calls of the returned FuncMap, so it should be
+// created for every execution, as with WithState:
+//
+//	t := funcmaps.New(fm, funcmaps.WithState(func(*funcmaps.ExecState) funcmaps.FuncMap {
+//		return funcmaps.Limit(fm, opts)
+//	}))
+func Limit(fm FuncMap, opts LimitOptions) FuncMap {
+	var calls int64
+	return wrapFuncs(fm, func(name string, args []reflect.Value, next func([]reflect.Value) []reflect.Value) []reflect.Value {
+		typ := reflect.TypeOf(fm[name])
+		if opts.MaxCalls > 0 && atomic.AddInt64(&calls, 1) > opts.MaxCalls {
+			return failCall(typ, fmt.Errorf("%w: more than %d calls", ErrLimitExceeded, opts.MaxCalls))
+		}
+		if guard, ok := outputGuards[name]; ok && opts.MaxOutputBytes > 0 {
+			if err := guard(opts.MaxOutputBytes)(callArgs(typ, args)); err != nil {
+				return failCall(typ, fmt.Errorf("%w: %v", ErrLimitExceeded, err))
+			}
+		}
+		var out []reflect.Value
+		if opts.Timeout > 0 {
+			var err error
+			if out, err = callTimeout(next, args, opts.Timeout); err != nil {
+				return failCall(typ, err)
+			}
+		} else {
+			out = next(args)
+		}
+		if opts.MaxOutputBytes > 0 {
+			for _, v := range out {
+				if n := outputLen(v); n > opts.MaxOutputBytes {
+					return failCall(typ, fmt.Errorf("%w: output of %d bytes exceeds %d", ErrLimitExceeded, n, opts.MaxOutputBytes))
+				}
+			}
+		}
+		return out
+	})
+}
+
+// callTimeout calls next(args), returning an error if it does not return
+// within timeout. Panics of next are raised again by the caller.
+func callTimeout(next func([]reflect.Value) []reflect.Value, args []reflect.Value, timeout time.Duration) ([]reflect.Value, error) {
+	type result struct {
+		out   []reflect.Value
+		panic interface{}
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- result{panic: p}
+			}
+		}()
+		done <- result{out: next(args)}
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		if r.panic != nil {
+			panic(r.panic)
+		}
+		return r.out, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("%w: timed out after %v", ErrLimitExceeded, timeout)
+	}
+}
+
+// outputLen returns the length of a string or byte slice result, which
+// includes html/template content types, or -1.
+func outputLen(v reflect.Value) int {
+	switch {
+	case v.Kind() == reflect.String:
+		return v.Len()
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return v.Len()
+	case v.Kind() == reflect.Interface && !v.IsNil():
+		return outputLen(v.Elem())
+	}
+	return -1
+}
@@ -0,0 +1,46 @@
+package funcmaps
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLimitOutput(t *testing.T) {
+	fm := Limit(Default(), LimitOptions{MaxOutputBytes: 10})
+	if got, err := execute(fm, `{{repeat 2 "ab"}}`, nil); err != nil || got != "abab" {
+		t.Errorf("repeat 2: got %q, %v", got, err)
+	}
+	for _, text := range []string{`{{repeat 1000000000 "x"}}`, `{{json .}}`} {
+		_, err := execute(fm, text, strings.Repeat("x", 20))
+		if err == nil || !strings.Contains(err.Error(), ErrLimitExceeded.Error()) {
+			t.Errorf("%s: got error %v, want %v", text, err, ErrLimitExceeded)
+		}
+	}
+}
+
+func TestLimitCalls(t *testing.T) {
+	fm := Limit(Default(), LimitOptions{MaxCalls: 3})
+	_, err := execute(fm, `{{range seq 1 5}}{{upper "x"}}{{end}}`, nil)
+	if err == nil || !strings.Contains(err.Error(), "more than 3 calls") {
+		t.Errorf("got error %v, want more than 3 calls", err)
+	}
+}
+
+func TestLimitTimeout(t *testing.T) {
+	slow := FuncMap{"slow": func() string { time.Sleep(50 * time.Millisecond); return "done" }}
+	fm := Limit(slow, LimitOptions{Timeout: time.Millisecond})
+	_, err := execute(fm, `{{slow}}`, nil)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("got error %v, want timed out", err)
+	}
+	call := fm["slow"].(func() string)
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("calling slow: got panic %v, want %v", err, ErrLimitExceeded)
+		}
+	}()
+	call()
+}
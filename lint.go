@@ -0,0 +1,157 @@
+package funcmaps
+
+import (
+	"fmt"
+	"io/fs"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// Issue is a problem found by Lint.
+type Issue struct {
+	Template string // name of the file
+	Line     int
+	Col      int    // or 0 if unknown
+	Func     string // function called, or "" for syntax errors
+	Message  string
+}
+
+// String returns the issue as "name:line:col: message".
+func (i Issue) String() string {
+	if i.Col == 0 {
+		return fmt.Sprintf("%s:%d: %s", i.Template, i.Line, i.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", i.Template, i.Line, i.Col, i.Message)
+}
+
+// Lint parses every file of fsys as a template, except hidden files and
+// directories, and reports the syntax errors, the calls of functions that
+// are not in fm or are given the wrong number of arguments, and the calls
+// of functions returning unescaped content, such as unsafeHTML, so that CI
+// can catch template breakage before deploying:
+//
+//	issues, err := funcmaps.Lint(os.DirFS("templates"), fm)
+//	for _, issue := range issues {
+//		fmt.Println(issue)
+//	}
+//
+// Issues are sorted by file and position. The error is only for failures
+// reading fsys.
+func Lint(fsys fs.FS, fm FuncMap) ([]Issue, error) {
+	var names []string
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name != "." && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var issues []Issue
+	var trees []*parse.Tree
+	for _, name := range names {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		// parsed separately, so that templates defined by several files
+		// are not reported as redefined
+		fileTrees := map[string]*parse.Tree{}
+		if err := parseUnchecked(name, string(b), fileTrees); err != nil {
+			issues = append(issues, parseIssue(name, err))
+			continue
+		}
+		for _, tree := range fileTrees {
+			trees = append(trees, tree)
+		}
+	}
+	for _, tree := range trees {
+		tree := tree
+		walkFuncs(tree.Root, func(id *parse.IdentifierNode, nargs int) {
+			if msg := lintCall(fm, id.Ident, nargs); msg != "" {
+				issue := Issue{Func: id.Ident, Message: msg}
+				issue.Template, issue.Line, issue.Col = position(tree, id)
+				issues = append(issues, issue)
+			}
+		})
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Template != b.Template {
+			return a.Template < b.Template
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Col < b.Col
+	})
+	return issues, nil
+}
+
+// lintCall returns the problem with calling the function name of fm with
+// nargs arguments, or "".
+func lintCall(fm FuncMap, name string, nargs int) string {
+	if builtins[name] {
+		return ""
+	}
+	fn, ok := fm[name]
+	if !ok {
+		msg := fmt.Sprintf("function %q not defined", name)
+		if names := SuggestFunc(name, fm); len(names) > 0 {
+			msg += fmt.Sprintf(" (did you mean %q?)", names[0])
+		}
+		return msg
+	}
+	if typ := reflect.TypeOf(fn); typ != nil && typ.Kind() == reflect.Func {
+		want := typ.NumIn()
+		switch {
+		case typ.IsVariadic() && nargs < want-1:
+			return fmt.Sprintf("function %q called with %d arguments, wants at least %d", name, nargs, want-1)
+		case !typ.IsVariadic() && nargs != want:
+			return fmt.Sprintf("function %q called with %d arguments, wants %d", name, nargs, want)
+		}
+	}
+	if funcRisk(name, fn, nil) == RiskUnescapedOutput {
+		return fmt.Sprintf("function %q returns unescaped content", name)
+	}
+	return ""
+}
+
+// position returns the file, line and column of n in tree.
+func position(tree *parse.Tree, n parse.Node) (string, int, int) {
+	loc, _ := tree.ErrorContext(n)
+	// loc is "name:line:col", where name may contain colons
+	parts := strings.Split(loc, ":")
+	if len(parts) < 3 {
+		return tree.ParseName, 0, 0
+	}
+	line, _ := strconv.Atoi(parts[len(parts)-2])
+	col, _ := strconv.Atoi(parts[len(parts)-1])
+	return strings.Join(parts[:len(parts)-2], ":"), line, col
+}
+
+var parseErrorRe = regexp.MustCompile(`^template: (.*?):(\d+): (.*)$`)
+
+// parseIssue returns the syntax error err of the file name as an Issue.
+func parseIssue(name string, err error) Issue {
+	issue := Issue{Template: name, Message: err.Error()}
+	if m := parseErrorRe.FindStringSubmatch(err.Error()); m != nil {
+		issue.Line, _ = strconv.Atoi(m[2])
+		issue.Message = m[3]
+	}
+	return issue
+}
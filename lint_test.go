@@ -0,0 +1,39 @@
+package funcmaps
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLint(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ok.html":       {Data: []byte(`{{toUpper .}} {{seq 1 3}} {{join2 ", " 1 2 3}} {{partial "card" .}} {{counter "n"}} {{. | trim "-"}}`)},
+		"arity.html":    {Data: []byte("{{toUpper . \"x\"}}\n{{seq 1}}\n{{now 1}}")},
+		"unknown.html":  {Data: []byte(`{{toUppr .}}`)},
+		"unsafe.html":   {Data: []byte(`{{unsafeHTML .}}`)},
+		"syntax.html":   {Data: []byte(`{{if .}}`)},
+		".hidden.html":  {Data: []byte(`{{nope}}`)},
+		"sub/deep.html": {Data: []byte(`{{define "x"}}{{nope}}{{end}}`)},
+	}
+	issues, err := Lint(fsys, Combined(All(), TemplateFuncs(Counters)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`arity.html:1:2: function "toUpper" called with 2 arguments, wants 1`,
+		`arity.html:2:2: function "seq" called with 1 arguments, wants at least 2`,
+		`arity.html:3:2: function "now" called with 1 arguments, wants 0`,
+		`sub/deep.html:1:16: function "nope" not defined`,
+		`syntax.html:1: unexpected EOF`,
+		`unknown.html:1:2: function "toUppr" not defined (did you mean "toUpper"?)`,
+		`unsafe.html:1:2: function "unsafeHTML" returns unescaped content`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got issues\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,36 @@
+package funcmaps
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// Markup returns functions producing HTML from plain text.
+// Their input is always escaped, so their output is safe to include in
+// html/template output.
+func Markup() FuncMap {
+	return FuncMap{
+		"highlightMatch": HighlightMatch,
+	}
+}
+
+// HighlightMatch escapes text and wraps every case-insensitive occurrence of
+// query in a mark element, for search results.
+func HighlightMatch(query, text string) template.HTML {
+	if strings.TrimSpace(query) == "" {
+		return template.HTML(template.HTMLEscapeString(text))
+	}
+	re := regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+	var b strings.Builder
+	last := 0
+	for _, m := range re.FindAllStringIndex(text, -1) {
+		b.WriteString(template.HTMLEscapeString(text[last:m[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(template.HTMLEscapeString(text[m[0]:m[1]]))
+		b.WriteString("</mark>")
+		last = m[1]
+	}
+	b.WriteString(template.HTMLEscapeString(text[last:]))
+	return template.HTML(b.String())
+}
@@ -0,0 +1,155 @@
+package funcmaps
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MergePolicy chooses how DeepMerge combines values present in more than
+// one map.
+type MergePolicy struct {
+	AppendLists  bool // lists are concatenated, instead of the last one winning
+	NilOverwrite bool // nil values replace earlier values, instead of being ignored
+	StrictTypes  bool // a map and a non-map for the same key are an error, instead of the last one winning
+}
+
+// ParseMergePolicy parses a comma separated list of policy settings, for
+// templates:
+//
+//	lists=append or lists=replace (default)
+//	nil=overwrite or nil=skip (default)
+//	conflict=error or conflict=replace (default)
+func ParseMergePolicy(s string) (MergePolicy, error) {
+	var p MergePolicy
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		switch field {
+		case "lists=append":
+			p.AppendLists = true
+		case "lists=replace":
+			p.AppendLists = false
+		case "nil=overwrite":
+			p.NilOverwrite = true
+		case "nil=skip":
+			p.NilOverwrite = false
+		case "conflict=error":
+			p.StrictTypes = true
+		case "conflict=replace":
+			p.StrictTypes = false
+		default:
+			return p, fmt.Errorf("invalid merge policy %q", field)
+		}
+	}
+	return p, nil
+}
+
+// DeepMergeWithPolicy is DeepMerge with the policy parsed by
+// ParseMergePolicy, for layered configuration such as values.yaml files:
+//
+//	{{$values := deepMergeWithPolicy "lists=append" .Defaults .Env .Overrides}}
+func DeepMergeWithPolicy(policy string, maps ...interface{}) (map[string]interface{}, error) {
+	p, err := ParseMergePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	return DeepMerge(p, maps...)
+}
+
+// DeepMerge merges maps into a new map, later maps taking precedence, and
+// merging the maps they have for the same key recursively. Maps of any key
+// type are accepted, such as those decoded from YAML, and are keyed by the
+// string representation of their keys in the result. The maps are not
+// modified.
+func DeepMerge(p MergePolicy, maps ...interface{}) (map[string]interface{}, error) {
+	rs := map[string]interface{}{}
+	for i, m := range maps {
+		if m == nil {
+			continue
+		}
+		src, ok := stringMap(m)
+		if !ok {
+			return nil, fmt.Errorf("deepMerge: argument %d is a %T, not a map", i+1, m)
+		}
+		if err := p.merge(rs, src, ""); err != nil {
+			return nil, err
+		}
+	}
+	return rs, nil
+}
+
+// merge merges src into dst, which is owned by the result.
+func (p MergePolicy) merge(dst, src map[string]interface{}, path string) error {
+	for k, v := range src {
+		old, exists := dst[k]
+		switch {
+		case v == nil:
+			if p.NilOverwrite || !exists {
+				dst[k] = nil
+			}
+			continue
+		case !exists || old == nil:
+			dst[k] = deepCopy(v)
+			continue
+		}
+		om, oldIsMap := old.(map[string]interface{})
+		vm, newIsMap := stringMap(v)
+		switch {
+		case oldIsMap && newIsMap:
+			if err := p.merge(om, vm, path+k+"."); err != nil {
+				return err
+			}
+		case oldIsMap != newIsMap && p.StrictTypes:
+			return fmt.Errorf("deepMerge: %s%s is a %T and a %T", path, k, old, v)
+		case p.AppendLists && isList(old) && isList(v):
+			a, _ := listValues(old)
+			b, _ := listValues(v)
+			dst[k] = append(a, deepCopy(b).([]interface{})...)
+		default:
+			dst[k] = deepCopy(v)
+		}
+	}
+	return nil
+}
+
+// stringMap returns the entries of a map keyed by the string representation
+// of its keys.
+func stringMap(m interface{}) (map[string]interface{}, bool) {
+	if sm, ok := m.(map[string]interface{}); ok {
+		return sm, true
+	}
+	v, isNil := indirect(reflect.ValueOf(m))
+	if isNil || v.Kind() != reflect.Map {
+		return nil, false
+	}
+	rs := make(map[string]interface{}, v.Len())
+	for r := v.MapRange(); r.Next(); {
+		rs[stringify(r.Key())] = r.Value().Interface()
+	}
+	return rs, true
+}
+
+// deepCopy copies maps, as map[string]interface{}, and lists, as
+// []interface{}, so that merging into the result never modifies the
+// arguments.
+func deepCopy(v interface{}) interface{} {
+	if m, ok := stringMap(v); ok {
+		rs := make(map[string]interface{}, len(m))
+		for k, e := range m {
+			rs[k] = deepCopy(e)
+		}
+		return rs
+	}
+	if isList(v) {
+		values, _ := listValues(v)
+		rs := make([]interface{}, len(values))
+		for i, e := range values {
+			rs[i] = deepCopy(e)
+		}
+		return rs
+	}
+	return v
+}
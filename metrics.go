@@ -0,0 +1,131 @@
+package funcmaps
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Executor executes named templates. It is implemented by both
+// *html/template.Template and *text/template.Template.
+type Executor interface {
+	ExecuteTemplate(w io.Writer, name string, data interface{}) error
+}
+
+// RenderMetrics records how long templates take to render, per route and
+// template name, and publishes the results with expvar.
+//
+// Wrap the handlers of each route with Wrap, and render with ExecuteTemplate.
+// RenderMetrics is itself an http.Handler serving the results as JSON; they
+// are also included in the standard /debug/vars expvar endpoint.
+type RenderMetrics struct {
+	mu     sync.Mutex // serializes creation of new series
+	routes *expvar.Map
+}
+
+// NewRenderMetrics returns RenderMetrics published in expvar as name.
+// Like expvar.Publish, it panics if name is already in use.
+func NewRenderMetrics(name string) *RenderMetrics {
+	return &RenderMetrics{routes: expvar.NewMap(name)}
+}
+
+type renderRouteKey struct{}
+
+type renderRoute struct {
+	metrics *RenderMetrics
+	route   string
+}
+
+// Wrap returns a handler recording renders made through ExecuteTemplate
+// while serving h under the given route name. Use route names such as
+// "/users/{id}" rather than raw paths, to keep the number of series small.
+func (m *RenderMetrics) Wrap(route string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), renderRouteKey{}, renderRoute{metrics: m, route: route})
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ServeHTTP writes the recorded metrics as JSON.
+func (m *RenderMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	io.WriteString(w, m.routes.String())
+}
+
+// Record records a render of the template name for route.
+func (m *RenderMetrics) Record(route, name string, d time.Duration, err error) {
+	templates, ok := m.routes.Get(route).(*expvar.Map)
+	if !ok {
+		m.mu.Lock()
+		if templates, ok = m.routes.Get(route).(*expvar.Map); !ok {
+			templates = new(expvar.Map).Init()
+			m.routes.Set(route, templates)
+		}
+		m.mu.Unlock()
+	}
+	stat, ok := templates.Get(name).(*renderStat)
+	if !ok {
+		m.mu.Lock()
+		if stat, ok = templates.Get(name).(*renderStat); !ok {
+			stat = &renderStat{}
+			templates.Set(name, stat)
+		}
+		m.mu.Unlock()
+	}
+	stat.observe(d, err)
+}
+
+// ExecuteTemplate executes the template name of t, recording its duration
+// with the RenderMetrics of the route r is served under, if any.
+func ExecuteTemplate(r *http.Request, w io.Writer, t Executor, name string, data interface{}) error {
+	start := time.Now()
+	err := t.ExecuteTemplate(w, name, data)
+	if rr, ok := r.Context().Value(renderRouteKey{}).(renderRoute); ok {
+		rr.metrics.Record(rr.route, name, time.Since(start), err)
+	}
+	return err
+}
+
+// renderStat is an expvar.Var summarizing the renders of one template.
+type renderStat struct {
+	mu     sync.Mutex
+	count  int64
+	errors int64
+	total  time.Duration
+	max    time.Duration
+}
+
+func (s *renderStat) observe(d time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count++
+	if err != nil {
+		s.errors++
+	}
+	s.total += d
+	if d > s.max {
+		s.max = d
+	}
+}
+
+// String returns the summary as JSON, as required by expvar.Var.
+func (s *renderStat) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var mean time.Duration
+	if s.count > 0 {
+		mean = s.total / time.Duration(s.count)
+	}
+	b, _ := json.Marshal(map[string]interface{}{
+		"count":    s.count,
+		"errors":   s.errors,
+		"total_ns": int64(s.total),
+		"mean_ns":  int64(mean),
+		"max_ns":   int64(s.max),
+	})
+	return string(b)
+}
@@ -0,0 +1,122 @@
+package funcmaps
+
+import (
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// MIME returns functions for media types, for download pages and email
+// attachments:
+//
+//	mimeByExt ".png"                => "image/png"
+//	extByMime "image/jpeg"          => ".jpg"
+//	isImageMime "image/svg+xml"     => true
+//	contentDisposition "résumé.pdf" => `attachment; filename="r_sum_.pdf"; filename*=UTF-8''r%C3%A9sum%C3%A9.pdf`
+func MIME() FuncMap {
+	return FuncMap{
+		"mimeByExt":   MIMEByExt,
+		"extByMime":   ExtByMIME,
+		"isImageMime": IsImageMIME,
+		"contentDisposition": func(filename string) string {
+			return ContentDisposition("attachment", filename)
+		},
+	}
+}
+
+// preferredExts are the usual extensions of media types with several.
+var preferredExts = map[string]string{
+	"image/jpeg":               ".jpg",
+	"image/tiff":               ".tif",
+	"image/svg+xml":            ".svg",
+	"text/html":                ".html",
+	"text/plain":               ".txt",
+	"audio/mpeg":               ".mp3",
+	"video/mpeg":               ".mpeg",
+	"application/octet-stream": ".bin",
+	"application/javascript":   ".js",
+	"text/javascript":          ".js",
+}
+
+// MIMEByExt returns the media type of the file extension ext, with or
+// without its leading dot, or "application/octet-stream" if it is unknown.
+func MIMEByExt(ext string) string {
+	if ext != "" && ext[0] != '.' {
+		ext = "." + ext
+	}
+	if typ := mime.TypeByExtension(strings.ToLower(ext)); typ != "" {
+		return typ
+	}
+	return "application/octet-stream"
+}
+
+// ExtByMIME returns the usual file extension of the media type typ, with
+// its leading dot, or "" if it is unknown. Parameters such as charset are
+// ignored.
+func ExtByMIME(typ string) string {
+	t, _, err := mime.ParseMediaType(typ)
+	if err != nil {
+		return ""
+	}
+	if ext, ok := preferredExts[t]; ok {
+		return ext
+	}
+	exts, err := mime.ExtensionsByType(t)
+	if err != nil || len(exts) == 0 {
+		return ""
+	}
+	return exts[0]
+}
+
+// IsImageMIME reports whether typ is an image media type.
+func IsImageMIME(typ string) bool {
+	t, _, err := mime.ParseMediaType(typ)
+	return err == nil && strings.HasPrefix(t, "image/")
+}
+
+// ContentDisposition returns a Content-Disposition header value of the
+// disposition typ, "attachment" or "inline", for filename. Names that are
+// not printable ASCII get an ASCII fallback, with other characters
+// replaced by "_", and the UTF-8 name in the filename* parameter, as
+// described in RFC 6266.
+func ContentDisposition(typ, filename string) string {
+	var ascii strings.Builder
+	plain := true
+	for _, r := range filename {
+		switch {
+		case r < 0x20 || r == 0x7f || r == utf8.RuneError:
+			plain = false
+		case r >= utf8.RuneSelf:
+			ascii.WriteByte('_')
+			plain = false
+		case r == '"' || r == '\\':
+			ascii.WriteByte('\\')
+			ascii.WriteRune(r)
+		default:
+			ascii.WriteRune(r)
+		}
+	}
+	v := typ + `; filename="` + ascii.String() + `"`
+	if !plain {
+		v += "; filename*=UTF-8''" + rfc5987Escape(filename)
+	}
+	return v
+}
+
+// rfc5987Escape percent-encodes the bytes of s other than the attr-char of
+// RFC 5987.
+func rfc5987Escape(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("!#$&+-.^_`|~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&15])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,210 @@
+package funcmaps
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Minifier minifies s, which holds content of the given media type:
+// "text/html", "text/css" or "application/javascript".
+//
+// The method set matches (*minify.M).String from github.com/tdewolff/minify,
+// so a configured tdewolff minifier can be used directly.
+type Minifier interface {
+	String(mediatype, s string) (string, error)
+}
+
+// BasicMinifier is a conservative Minifier that only removes comments and
+// redundant whitespace. It is used when Minify is given a nil Minifier.
+var BasicMinifier Minifier = basicMinifier{}
+
+// Minify returns functions shrinking HTML, CSS and JavaScript with m.
+//
+// The minify functions keep the type of their argument, so minifying a
+// template.HTML value returns template.HTML and a string returns a string.
+func Minify(m Minifier) FuncMap {
+	if m == nil {
+		m = BasicMinifier
+	}
+	return FuncMap{
+		"minifyHTML":         func(v interface{}) (interface{}, error) { return minifyValue(m, "text/html", v) },
+		"minifyCSS":          func(v interface{}) (interface{}, error) { return minifyValue(m, "text/css", v) },
+		"minifyJS":           func(v interface{}) (interface{}, error) { return minifyValue(m, "application/javascript", v) },
+		"collapseWhitespace": CollapseWhitespace,
+	}
+}
+
+// CollapseWhitespace replaces each run of whitespace in s with a single space,
+// and trims leading and trailing whitespace.
+func CollapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func minifyValue(m Minifier, mediatype string, v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case template.HTML:
+		s, err := m.String(mediatype, string(v))
+		return template.HTML(s), err
+	case template.CSS:
+		s, err := m.String(mediatype, string(v))
+		return template.CSS(s), err
+	case template.JS:
+		s, err := m.String(mediatype, string(v))
+		return template.JS(s), err
+	case string:
+		return m.String(mediatype, v)
+	case []byte:
+		return m.String(mediatype, string(v))
+	}
+	return m.String(mediatype, stringifyValue(v))
+}
+
+type basicMinifier struct{}
+
+func (basicMinifier) String(mediatype, s string) (string, error) {
+	switch mediatype {
+	case "text/html":
+		return minifyHTML(s), nil
+	case "text/css":
+		return minifyCSS(s), nil
+	case "application/javascript", "text/javascript":
+		return minifyJS(s), nil
+	}
+	return s, nil
+}
+
+var (
+	htmlRawBlock = regexp.MustCompile(`(?is)<pre\b.*?</pre>|<textarea\b.*?</textarea>|<script\b.*?</script>|<style\b.*?</style>`)
+	htmlComment  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	whitespace   = regexp.MustCompile(`\s+`)
+)
+
+// minifyHTML removes comments and collapses whitespace, leaving pre, textarea,
+// script and style elements untouched. Conditional comments are kept.
+func minifyHTML(s string) string {
+	var b strings.Builder
+	last := 0
+	collapse := func(chunk string) {
+		chunk = htmlComment.ReplaceAllStringFunc(chunk, func(c string) string {
+			if strings.HasPrefix(c, "<!--[if") {
+				return c
+			}
+			return ""
+		})
+		b.WriteString(whitespace.ReplaceAllString(chunk, " "))
+	}
+	for _, m := range htmlRawBlock.FindAllStringIndex(s, -1) {
+		collapse(s[last:m[0]])
+		b.WriteString(s[m[0]:m[1]])
+		last = m[1]
+	}
+	collapse(s[last:])
+	return strings.TrimSpace(b.String())
+}
+
+// minifyCSS removes comments and whitespace around punctuation, leaving
+// quoted strings untouched.
+func minifyCSS(s string) string {
+	const punct = "{}:;,>"
+	out := make([]byte, 0, len(s))
+	space := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return string(out)
+			}
+			i += end + 3
+			continue
+		case unicode.IsSpace(rune(c)):
+			space = len(out) > 0
+			continue
+		case strings.IndexByte(punct, c) >= 0:
+			// a space before a colon may be a descendant combinator, as
+			// in "div :first-child"
+			if c == ':' && space && strings.IndexByte(punct, out[len(out)-1]) < 0 {
+				out = append(out, ' ')
+			}
+			if c == '}' && len(out) > 0 && out[len(out)-1] == ';' {
+				out = out[:len(out)-1]
+			}
+			out = append(out, c)
+			space = false
+			continue
+		}
+		if space && strings.IndexByte(punct, out[len(out)-1]) < 0 {
+			out = append(out, ' ')
+		}
+		space = false
+		if c == '"' || c == '\'' {
+			j := i + 1
+			for j < len(s) && s[j] != c {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				j = len(s) - 1
+			}
+			out = append(out, s[i:j+1]...)
+			i = j
+			continue
+		}
+		out = append(out, c)
+	}
+	return string(out)
+}
+
+// minifyJS trims every line and drops blank lines and lines holding only a
+// line comment, keeping the lines of multi-line template literals as they
+// are. It does not rewrite code, but finds template literals by counting
+// backticks outside of strings and comments, so a backtick in a regular
+// expression literal, as in /`/, breaks scripts.
+func minifyJS(s string) string {
+	lines := strings.Split(s, "\n")
+	rs := lines[:0]
+	inTemplate := false
+	for _, line := range lines {
+		if inTemplate {
+			rs = append(rs, line)
+			inTemplate = openTemplateLiteral(line, true)
+			continue
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		rs = append(rs, line)
+		inTemplate = openTemplateLiteral(line, false)
+	}
+	return strings.Join(rs, "\n")
+}
+
+// openTemplateLiteral reports whether a template literal is open at the end
+// of line, given whether one was open at its start.
+func openTemplateLiteral(line string, open bool) bool {
+	var quote byte
+	if open {
+		quote = '`'
+	}
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			return false
+		}
+	}
+	return quote == '`'
+}
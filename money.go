@@ -0,0 +1,243 @@
+package funcmaps
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// Money returns functions for amounts of money, which are kept in integer
+// minor units such as cents, so that invoice totals do not suffer from
+// floating point rounding:
+//
+//	money 1999 "USD"                 => 19.99 US dollars
+//	money "19.99" "USD"              => the same
+//	moneyAdd $subtotal $shipping     => the sum
+//	moneyMul "0.0825" $subtotal      => the product, rounded half away from zero
+//	formatMoney $total               => "$1,234.56"
+func Money() FuncMap {
+	return FuncMap{
+		"money":       NewAmount,
+		"moneyAdd":    AddAmounts,
+		"moneyMul":    MulAmount,
+		"formatMoney": FormatMoney,
+	}
+}
+
+// Currency is the ISO 4217 metadata of a currency.
+type Currency struct {
+	Code   string // alphabetic code, such as "USD"
+	Number int    // numeric code, such as 840
+	Digits int    // number of minor unit digits, such as 2 for cents
+	Symbol string // symbol used by FormatMoney, such as "$"
+	Name   string
+}
+
+// Currencies are the currencies known to the Money functions, by code.
+var Currencies = map[string]Currency{
+	"AUD": {"AUD", 36, 2, "A$", "Australian dollar"},
+	"BHD": {"BHD", 48, 3, "BD", "Bahraini dinar"},
+	"BRL": {"BRL", 986, 2, "R$", "Brazilian real"},
+	"CAD": {"CAD", 124, 2, "CA$", "Canadian dollar"},
+	"CHF": {"CHF", 756, 2, "CHF ", "Swiss franc"},
+	"CLP": {"CLP", 152, 0, "CLP$", "Chilean peso"},
+	"CNY": {"CNY", 156, 2, "CN¥", "Renminbi"},
+	"CZK": {"CZK", 203, 2, "Kč ", "Czech koruna"},
+	"DKK": {"DKK", 208, 2, "kr ", "Danish krone"},
+	"EUR": {"EUR", 978, 2, "€", "Euro"},
+	"GBP": {"GBP", 826, 2, "£", "Pound sterling"},
+	"HKD": {"HKD", 344, 2, "HK$", "Hong Kong dollar"},
+	"HUF": {"HUF", 348, 2, "Ft ", "Hungarian forint"},
+	"IDR": {"IDR", 360, 2, "Rp ", "Indonesian rupiah"},
+	"ILS": {"ILS", 376, 2, "₪", "Israeli new shekel"},
+	"INR": {"INR", 356, 2, "₹", "Indian rupee"},
+	"JOD": {"JOD", 400, 3, "JD ", "Jordanian dinar"},
+	"JPY": {"JPY", 392, 0, "¥", "Japanese yen"},
+	"KRW": {"KRW", 410, 0, "₩", "South Korean won"},
+	"KWD": {"KWD", 414, 3, "KD ", "Kuwaiti dinar"},
+	"MXN": {"MXN", 484, 2, "MX$", "Mexican peso"},
+	"NOK": {"NOK", 578, 2, "kr ", "Norwegian krone"},
+	"NZD": {"NZD", 554, 2, "NZ$", "New Zealand dollar"},
+	"PLN": {"PLN", 985, 2, "zł ", "Polish złoty"},
+	"SEK": {"SEK", 752, 2, "kr ", "Swedish krona"},
+	"SGD": {"SGD", 702, 2, "S$", "Singapore dollar"},
+	"THB": {"THB", 764, 2, "฿", "Thai baht"},
+	"TRY": {"TRY", 949, 2, "₺", "Turkish lira"},
+	"USD": {"USD", 840, 2, "$", "United States dollar"},
+	"ZAR": {"ZAR", 710, 2, "R ", "South African rand"},
+}
+
+// Amount is an amount of money in the minor units of its currency.
+type Amount struct {
+	Units    int64  // minor units, such as cents
+	Currency string // ISO 4217 code
+}
+
+// String returns the amount formatted by FormatMoney.
+func (a Amount) String() string {
+	return FormatMoney(a)
+}
+
+var errMoneyOverflow = errors.New("money: amount out of range")
+
+// currency returns the metadata of code, which is case-insensitive.
+func currency(code string) (Currency, error) {
+	c, ok := Currencies[strings.ToUpper(code)]
+	if !ok {
+		return Currency{}, fmt.Errorf("money: unknown currency %q", code)
+	}
+	return c, nil
+}
+
+// NewAmount returns an amount of the currency. An integer amount is in
+// minor units, and a string or floating point amount, such as "19.99", in
+// major units with at most the minor unit digits of the currency. Strings
+// are plain decimals, with commas only as thousands separators.
+func NewAmount(amount interface{}, code string) (Amount, error) {
+	c, err := currency(code)
+	if err != nil {
+		return Amount{}, err
+	}
+	switch v := amount.(type) {
+	case Amount:
+		if v.Currency != c.Code {
+			return Amount{}, fmt.Errorf("money: cannot convert %s to %s", v.Currency, c.Code)
+		}
+		return v, nil
+	case string, float32, float64:
+		units, err := parseUnits(strings.TrimSpace(cast.ToString(v)), c.Digits)
+		if err != nil {
+			return Amount{}, err
+		}
+		return Amount{Units: units, Currency: c.Code}, nil
+	}
+	units, err := cast.ToInt64E(amount)
+	if err != nil {
+		return Amount{}, fmt.Errorf("money: invalid amount %v", amount)
+	}
+	return Amount{Units: units, Currency: c.Code}, nil
+}
+
+// decimalRe matches plain decimal numbers, with commas only as thousands
+// separators, so that "19,99" is not read as 1999.
+var decimalRe = regexp.MustCompile(`^[+-]?(\d+|\d{1,3}(,\d{3})+)(\.\d+)?$`)
+
+// parseDecimal parses the plain decimal number s, refusing the fractions
+// and exponents accepted by big.Rat.
+func parseDecimal(s string) (*big.Rat, bool) {
+	if !decimalRe.MatchString(s) {
+		return nil, false
+	}
+	return new(big.Rat).SetString(strings.ReplaceAll(s, ",", ""))
+}
+
+// parseUnits parses the decimal s into minor units with the given digits.
+func parseUnits(s string, digits int) (int64, error) {
+	r, ok := parseDecimal(s)
+	if !ok {
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(pow10(digits)))
+	if !r.IsInt() {
+		return 0, fmt.Errorf("money: amount %q has more than %d decimals", s, digits)
+	}
+	if !r.Num().IsInt64() {
+		return 0, errMoneyOverflow
+	}
+	return r.Num().Int64(), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// AddAmounts returns the sum of amounts, which must all be of the same
+// currency.
+func AddAmounts(amounts ...Amount) (Amount, error) {
+	if len(amounts) == 0 {
+		return Amount{}, errors.New("money: nothing to add")
+	}
+	sum := amounts[0]
+	for _, a := range amounts[1:] {
+		if a.Currency != sum.Currency {
+			return Amount{}, fmt.Errorf("money: cannot add %s to %s", a.Currency, sum.Currency)
+		}
+		if a.Units > 0 && sum.Units > math.MaxInt64-a.Units || a.Units < 0 && sum.Units < math.MinInt64-a.Units {
+			return Amount{}, errMoneyOverflow
+		}
+		sum.Units += a.Units
+	}
+	return sum, nil
+}
+
+// MulAmount returns a multiplied by factor, which is an integer, a float or
+// a decimal string such as "0.0825", rounded half away from zero to minor
+// units.
+func MulAmount(factor interface{}, a Amount) (Amount, error) {
+	var f *big.Rat
+	switch v := factor.(type) {
+	case string, float32, float64:
+		// the shortest decimal of a float, so that 0.1 is exactly 1/10
+		f, _ = parseDecimal(strings.TrimSpace(cast.ToString(v)))
+	default:
+		n, err := cast.ToInt64E(v)
+		if err != nil {
+			return Amount{}, fmt.Errorf("money: invalid factor %v", factor)
+		}
+		f = new(big.Rat).SetInt64(n)
+	}
+	if f == nil {
+		return Amount{}, fmt.Errorf("money: invalid factor %v", factor)
+	}
+	r := f.Mul(f, new(big.Rat).SetInt64(a.Units))
+	// round half away from zero: truncate |r| + 1/2
+	q := new(big.Int).Abs(r.Num())
+	q.Mul(q, big.NewInt(2)).Add(q, r.Denom())
+	q.Quo(q, new(big.Int).Mul(r.Denom(), big.NewInt(2)))
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	if !q.IsInt64() {
+		return Amount{}, errMoneyOverflow
+	}
+	return Amount{Units: q.Int64(), Currency: a.Currency}, nil
+}
+
+// FormatMoney returns a with the symbol of its currency, thousands
+// separators and its minor unit digits, such as "$1,234.56" or "-€3.50".
+// Amounts of unknown currencies are written with their code.
+func FormatMoney(a Amount) string {
+	c, ok := Currencies[a.Currency]
+	if !ok {
+		c = Currency{Code: a.Currency, Digits: 2, Symbol: a.Currency + " "}
+	}
+	units := new(big.Int).SetInt64(a.Units)
+	sign := ""
+	if units.Sign() < 0 {
+		sign = "-"
+		units.Neg(units)
+	}
+	major, minor := new(big.Int).QuoRem(units, pow10(c.Digits), new(big.Int))
+	s := groupThousands(major.String())
+	if c.Digits > 0 {
+		s += fmt.Sprintf(".%0*s", c.Digits, minor.String())
+	}
+	return sign + c.Symbol + s
+}
+
+// groupThousands inserts commas between groups of three digits.
+func groupThousands(digits string) string {
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package funcmaps
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Names returns functions formatting personal names.
+func Names() FuncMap {
+	return FuncMap{
+		"initials":       func(name string) string { return Initials(name, 2) },
+		"initialsN":      InitialsN,
+		"initialsLocale": func(lang, name string) string { return InitialsLocale(lang, 2, name) },
+		"possessive":     Possessive,
+		"formatName":     FormatName,
+	}
+}
+
+// Initials returns the uppercased first character of each word of name.
+//
+// If name has more than max words, only the first max-1 words and the last
+// word are used, so "Ada King Lovelace" with max 2 gives "AL", and with max
+// 1 gives "A". A max of zero or less means no limit. A character is a whole
+// grapheme cluster, so combining marks and emoji sequences are kept intact.
+func Initials(name string, max int) string {
+	return initials(name, max, unicode.SpecialCase(nil))
+}
+
+// InitialsN is Initials with the arguments swapped, for use in pipelines.
+func InitialsN(max int, name string) string {
+	return Initials(name, max)
+}
+
+// InitialsLocale is Initials using the casing rules of the language lang,
+// eg. "tr" uppercases "i" to "İ".
+func InitialsLocale(lang string, max int, name string) string {
+	return initials(name, max, localeCase(lang))
+}
+
+// Possessive returns the possessive form of name: "Ada" gives "Ada's" and
+// "Chris" gives "Chris'".
+func Possessive(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	if strings.HasSuffix(name, "s") || strings.HasSuffix(name, "S") {
+		return name + "'"
+	}
+	return name + "'s"
+}
+
+// FormatName formats a first and last name in one of the styles
+// "First Last", "Last, First", "F. Last" or "Last, F.".
+func FormatName(first, last, style string) (string, error) {
+	first, last = strings.TrimSpace(first), strings.TrimSpace(last)
+	initial := ""
+	if c := FirstGrapheme(first); c != "" {
+		rs := []rune(c)
+		rs[0] = unicode.ToUpper(rs[0])
+		initial = string(rs) + "."
+	}
+	var parts []string
+	switch style {
+	case "First Last":
+		parts = []string{first, last}
+	case "Last, First":
+		parts = []string{last + ",", first}
+	case "F. Last":
+		parts = []string{initial, last}
+	case "Last, F.":
+		parts = []string{last + ",", initial}
+	default:
+		return "", fmt.Errorf("formatName: unknown style %q", style)
+	}
+	if first == "" || last == "" {
+		// nothing to reorder or abbreviate
+		return strings.TrimSpace(first + " " + last), nil
+	}
+	return strings.Join(parts, " "), nil
+}
+
+func initials(name string, max int, c unicode.SpecialCase) string {
+	words := strings.Fields(name)
+	switch {
+	case max == 1 && len(words) > 1:
+		words = words[:1]
+	case max > 1 && len(words) > max:
+		words = append(words[:max-1], words[len(words)-1])
+	}
+	var b strings.Builder
+	for _, w := range words {
+		// skip leading punctuation, eg. quotes and parentheses
+		w = strings.TrimLeftFunc(w, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r) && !unicode.Is(unicode.So, r)
+		})
+		first := FirstGrapheme(w)
+		if first == "" {
+			continue
+		}
+		rs := []rune(first)
+		rs[0] = c.ToUpper(rs[0])
+		b.WriteString(string(rs))
+	}
+	return b.String()
+}
+
+// localeCase returns the special casing rules for the language lang.
+func localeCase(lang string) unicode.SpecialCase {
+	switch strings.ToLower(strings.SplitN(strings.Replace(lang, "_", "-", -1), "-", 2)[0]) {
+	case "tr", "az":
+		return unicode.TurkishCase
+	}
+	return nil
+}
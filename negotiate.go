@@ -0,0 +1,160 @@
+package funcmaps
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// negotiated is a format RenderNegotiated can respond with.
+type negotiated struct {
+	mediaType   string
+	contentType string
+	ext         string
+}
+
+// negotiatedFormats are the formats of RenderNegotiated, in order of
+// preference.
+var negotiatedFormats = []negotiated{
+	{"text/html", "text/html; charset=utf-8", ".html"},
+	{"application/json", "application/json", ".json"},
+	{"text/plain", "text/plain; charset=utf-8", ".txt"},
+}
+
+// RenderNegotiated responds to r with data rendered in the format preferred
+// by its Accept header, so that one handler serves both pages and API
+// clients. The formats are, in order of preference:
+//
+//   - HTML, from the template name+".html"
+//   - JSON, from the text template name+".json", or data encoded as JSON
+//   - plain text, from the text template name+".txt"
+//
+// HTML and text are only offered if their template is defined. It responds
+// with 406 Not Acceptable if no format is acceptable. The output is
+// buffered, so that errors are returned before anything is written.
+func (t *Templates) RenderNegotiated(w http.ResponseWriter, r *http.Request, name string, data interface{}) error {
+	set, err := t.current()
+	if err != nil {
+		return err
+	}
+	base := path.Clean(name)
+	var offers []negotiated
+	for _, f := range negotiatedFormats {
+		switch f.ext {
+		case ".html":
+			if set.master.Lookup(base+f.ext) == nil {
+				continue
+			}
+		case ".txt":
+			if set.text.Lookup(base+f.ext) == nil {
+				continue
+			}
+		}
+		offers = append(offers, f)
+	}
+	w.Header().Add("Vary", "Accept")
+	f, ok := negotiate(r.Header.Get("Accept"), offers)
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable)
+		return nil
+	}
+	var b bytes.Buffer
+	switch {
+	case f.ext == ".html":
+		err = t.execute(&b, base+f.ext, data, nil)
+	case set.text.Lookup(base+f.ext) != nil:
+		err = t.ExecuteText(&b, base+f.ext, data)
+	default:
+		err = json.NewEncoder(&b).Encode(data)
+	}
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", f.contentType)
+	_, err = b.WriteTo(w)
+	return err
+}
+
+// acceptRange is a media range of an Accept header.
+type acceptRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// negotiate returns the offer with the highest quality in the Accept
+// header accept, the first one on ties.
+func negotiate(accept string, offers []negotiated) (negotiated, bool) {
+	ranges := parseAccept(accept)
+	best, bestQ := -1, 0.0
+	for i, o := range offers {
+		typ, sub := splitMediaType(o.mediaType)
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			s := -1
+			switch {
+			case r.typ == typ && r.subtype == sub:
+				s = 2
+			case r.typ == typ && r.subtype == "*":
+				s = 1
+			case r.typ == "*" && r.subtype == "*":
+				s = 0
+			}
+			if s > specificity {
+				q, specificity = r.q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	if best < 0 {
+		return negotiated{}, false
+	}
+	return offers[best], true
+}
+
+// parseAccept returns the media ranges of an Accept header, or */* if it
+// is empty.
+func parseAccept(accept string) []acceptRange {
+	if strings.TrimSpace(accept) == "" {
+		return []acceptRange{{"*", "*", 1}}
+	}
+	var rs []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		typ, sub := splitMediaType(strings.TrimSpace(params[0]))
+		if typ == "" {
+			continue
+		}
+		r := acceptRange{typ, sub, 1}
+		for _, p := range params[1:] {
+			k, v, _ := cutString(strings.TrimSpace(p), "=")
+			if strings.EqualFold(k, "q") {
+				if q, err := strconv.ParseFloat(v, 64); err == nil {
+					r.q = q
+				}
+			}
+		}
+		rs = append(rs, r)
+	}
+	return rs
+}
+
+func splitMediaType(s string) (typ, subtype string) {
+	typ, subtype, ok := cutString(strings.ToLower(s), "/")
+	if !ok {
+		return "", ""
+	}
+	return typ, subtype
+}
+
+// cutString is strings.Cut, which needs Go 1.18.
+func cutString(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,88 @@
+package funcmaps
+
+import "fmt"
+
+// pageWindow is the number of page links in Paginator.PageNumbers.
+const pageWindow = 5
+
+// Paginator is one page of a list of items.
+type Paginator struct {
+	Items      []interface{} // items on the current page
+	Page       int           // current page, starting at 1
+	PerPage    int
+	TotalItems int
+	TotalPages int
+	HasPrev    bool
+	HasNext    bool
+	PrevPage   int // zero if there is no previous page
+	NextPage   int // zero if there is no next page
+
+	// PageNumbers is a window of page numbers around the current page,
+	// for rendering page links.
+	PageNumbers []int
+}
+
+// Paginate returns the given page of items, perPage items per page.
+// Pages are numbered from 1; out of range pages are clamped to the first or
+// last page.
+func Paginate(items interface{}, page, perPage int) (*Paginator, error) {
+	if perPage < 1 {
+		return nil, fmt.Errorf("paginate: invalid page size %d", perPage)
+	}
+	values, err := listValues(items)
+	if err != nil {
+		return nil, err
+	}
+	p := &Paginator{
+		PerPage:    perPage,
+		TotalItems: len(values),
+		TotalPages: (len(values) + perPage - 1) / perPage,
+	}
+	if p.TotalPages == 0 {
+		p.TotalPages = 1
+	}
+	switch {
+	case page < 1:
+		page = 1
+	case page > p.TotalPages:
+		page = p.TotalPages
+	}
+	p.Page = page
+	start := (page - 1) * perPage
+	end := start + perPage
+	if end > len(values) {
+		end = len(values)
+	}
+	p.Items = values[start:end]
+	if page > 1 {
+		p.HasPrev, p.PrevPage = true, page-1
+	}
+	if page < p.TotalPages {
+		p.HasNext, p.NextPage = true, page+1
+	}
+	p.PageNumbers = p.Window(pageWindow)
+	return p, nil
+}
+
+// Window returns up to n consecutive page numbers, keeping the current page
+// as close to the middle as possible, or nil if n is less than 1.
+func (p *Paginator) Window(n int) []int {
+	if n < 1 {
+		return nil
+	}
+	if n > p.TotalPages {
+		n = p.TotalPages
+	}
+	first := p.Page - n/2
+	if first > p.TotalPages-n+1 {
+		first = p.TotalPages - n + 1
+	}
+	if first < 1 {
+		first = 1
+	}
+	rs := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		rs = append(rs, first+i)
+	}
+	return rs
+}
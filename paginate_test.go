@@ -0,0 +1,26 @@
+package funcmaps
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPaginatorWindow(t *testing.T) {
+	p, err := Paginate(make([]int, 95), 5, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		n    int
+		want []int
+	}{
+		{5, []int{3, 4, 5, 6, 7}},
+		{20, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{0, nil},
+		{-3, nil},
+	} {
+		if got := p.Window(tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Window(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package funcmaps
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+	ttemplate "text/template"
+)
+
+// ParsePartials walks fsys and parses every file whose name starts with an
+// underscore as a partial template, named after its path without the
+// underscore and extension: "_card.html" is "card" and
+// "components/_button.html" is "components/button".
+//
+// Partials are executed with {{partial "card" .}}, or with the template
+// action like any other template.
+func (t *Templates) ParsePartials(fsys fs.FS) error {
+	return t.addSource(templateSource{
+		fsys:  fsys,
+		files: func() ([]string, error) { return partialFiles(fsys) },
+		parse: func(set *templateSet) error {
+			names, err := partialFiles(fsys)
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				b, err := fs.ReadFile(fsys, name)
+				if err != nil {
+					return err
+				}
+				if _, err := set.master.New(PartialName(name)).Parse(string(b)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// PartialName returns the template name ParsePartials gives the file name.
+func PartialName(name string) string {
+	dir, base := path.Split(name)
+	base = strings.TrimPrefix(base, "_")
+	return dir + strings.TrimSuffix(base, path.Ext(base))
+}
+
+func partialFiles(fsys fs.FS) ([]string, error) {
+	var rs []string
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasPrefix(d.Name(), "_") {
+			rs = append(rs, name)
+		}
+		return nil
+	})
+	return rs, err
+}
+
+var errNoPartials = errors.New("partial is only available when executing Templates")
+
+// partialFunc returns the partial function, executing partials of c.
+func partialFunc(c *template.Template) func(string, ...interface{}) (template.HTML, error) {
+	return func(name string, data ...interface{}) (template.HTML, error) {
+		if c == nil {
+			return "", errNoPartials
+		}
+		if c.Lookup(name) == nil {
+			return "", fmt.Errorf("partial %q not defined", name)
+		}
+		var arg interface{}
+		if len(data) > 0 {
+			arg = data[0]
+		}
+		var b bytes.Buffer
+		if err := c.ExecuteTemplate(&b, name, arg); err != nil {
+			return "", err
+		}
+		return template.HTML(b.String()), nil
+	}
+}
+
+// textPartialFunc is partialFunc for text templates.
+func textPartialFunc(c *ttemplate.Template) func(string, ...interface{}) (string, error) {
+	return func(name string, data ...interface{}) (string, error) {
+		if c == nil {
+			return "", errNoPartials
+		}
+		if c.Lookup(name) == nil {
+			return "", fmt.Errorf("partial %q not defined", name)
+		}
+		var arg interface{}
+		if len(data) > 0 {
+			arg = data[0]
+		}
+		var b bytes.Buffer
+		if err := c.ExecuteTemplate(&b, name, arg); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+}
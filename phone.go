@@ -0,0 +1,220 @@
+package funcmaps
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Phone returns functions validating and formatting phone numbers.
+//
+// The region is an ISO 3166-1 alpha-2 code used for numbers written without
+// a country calling code. Only the regions in PhoneRegions are supported.
+func Phone() FuncMap {
+	return FuncMap{
+		"formatPhone":  FormatPhone,
+		"isValidPhone": IsValidPhone,
+		"e164":         E164,
+	}
+}
+
+// phoneRegion is a small subset of the libphonenumber metadata for a region.
+type phoneRegion struct {
+	code    string         // country calling code
+	trunk   string         // national (trunk) prefix
+	valid   *regexp.Regexp // valid national significant numbers
+	formats []phoneFormat
+	intlSep string // separator between groups in international format
+}
+
+// phoneFormat formats national significant numbers matching leading.
+type phoneFormat struct {
+	leading  *regexp.Regexp
+	groups   []int
+	national string // printf layout for the groups, in national format
+}
+
+// PhoneRegions lists the regions supported by the Phone functions.
+var PhoneRegions = []string{"AU", "CA", "DE", "FR", "GB", "IN", "US"}
+
+var nanpRegion = &phoneRegion{
+	code:    "1",
+	trunk:   "1",
+	valid:   regexp.MustCompile(`^[2-9]\d{2}[2-9]\d{6}$`),
+	intlSep: "-",
+	formats: []phoneFormat{
+		{regexp.MustCompile(``), []int{3, 3, 4}, "(%s) %s-%s"},
+	},
+}
+
+var phoneMetadata = map[string]*phoneRegion{
+	"US": nanpRegion,
+	"CA": nanpRegion,
+	"GB": {
+		code:  "44",
+		trunk: "0",
+		valid: regexp.MustCompile(`^(?:[1-3]\d{8,9}|7\d{9}|[58]\d{9})$`),
+		formats: []phoneFormat{
+			{regexp.MustCompile(`^2`), []int{2, 4, 4}, "0%s %s %s"},
+			{regexp.MustCompile(`^[37-9]`), []int{4, 6}, "0%s %s"},
+			{regexp.MustCompile(`^1\d1|^11`), []int{3, 3, 4}, "0%s %s %s"},
+			{regexp.MustCompile(``), []int{4, 6}, "0%s %s"},
+		},
+	},
+	"FR": {
+		code:  "33",
+		trunk: "0",
+		valid: regexp.MustCompile(`^[1-9]\d{8}$`),
+		formats: []phoneFormat{
+			{regexp.MustCompile(``), []int{1, 2, 2, 2, 2}, "0%s %s %s %s %s"},
+		},
+	},
+	"DE": {
+		code:  "49",
+		trunk: "0",
+		valid: regexp.MustCompile(`^[1-9]\d{5,10}$`),
+		formats: []phoneFormat{
+			{regexp.MustCompile(`^1[5-7]`), []int{3, -1}, "0%s %s"},
+			{regexp.MustCompile(`^[2-9]0|^[3-9]\d{3}`), []int{2, -1}, "0%s %s"},
+			{regexp.MustCompile(``), []int{3, -1}, "0%s %s"},
+		},
+	},
+	"AU": {
+		code:  "61",
+		trunk: "0",
+		valid: regexp.MustCompile(`^[2-478]\d{8}$`),
+		formats: []phoneFormat{
+			{regexp.MustCompile(`^4`), []int{3, 3, 3}, "0%s %s %s"},
+			{regexp.MustCompile(``), []int{1, 4, 4}, "(0%s) %s %s"},
+		},
+	},
+	"IN": {
+		code:  "91",
+		trunk: "0",
+		valid: regexp.MustCompile(`^[1-9]\d{9}$`),
+		formats: []phoneFormat{
+			{regexp.MustCompile(``), []int{5, 5}, "0%s %s"},
+		},
+	},
+}
+
+// phoneNumber is a parsed phone number.
+type phoneNumber struct {
+	region string
+	meta   *phoneRegion
+	nsn    string // national significant number
+}
+
+// parsePhone parses number, written either in international format
+// ("+44 20 7946 0018", "0044 ...") or in the national format of region.
+func parsePhone(region, number string) (phoneNumber, error) {
+	raw := strings.TrimSpace(number)
+	var digits strings.Builder
+	for _, r := range raw {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	d := digits.String()
+	if d == "" {
+		return phoneNumber{}, fmt.Errorf("invalid phone number %q", number)
+	}
+	region = strings.ToUpper(region)
+
+	international := strings.HasPrefix(raw, "+")
+	if !international && strings.HasPrefix(d, "00") {
+		international, d = true, d[2:]
+	}
+	if international {
+		// prefer region, so +1 numbers keep the region they were given in
+		for _, r := range append([]string{region}, PhoneRegions...) {
+			meta, ok := phoneMetadata[r]
+			if ok && strings.HasPrefix(d, meta.code) {
+				return phoneNumber{region: r, meta: meta, nsn: d[len(meta.code):]}, nil
+			}
+		}
+		return phoneNumber{}, fmt.Errorf("unsupported country calling code in %q", number)
+	}
+
+	meta, ok := phoneMetadata[region]
+	if !ok {
+		return phoneNumber{}, fmt.Errorf("unsupported phone region %q", region)
+	}
+	if meta.trunk != "" && strings.HasPrefix(d, meta.trunk) && !meta.valid.MatchString(d) {
+		d = d[len(meta.trunk):]
+	}
+	return phoneNumber{region: region, meta: meta, nsn: d}, nil
+}
+
+func (n phoneNumber) valid() bool {
+	return n.meta.valid.MatchString(n.nsn)
+}
+
+// groups splits the number according to its format; a group size of -1
+// takes the remaining digits.
+func (n phoneNumber) groups() (phoneFormat, []interface{}) {
+	for _, f := range n.meta.formats {
+		if !f.leading.MatchString(n.nsn) {
+			continue
+		}
+		rs := make([]interface{}, 0, len(f.groups))
+		rest := n.nsn
+		for _, size := range f.groups {
+			if size < 0 || size > len(rest) {
+				size = len(rest)
+			}
+			rs = append(rs, rest[:size])
+			rest = rest[size:]
+		}
+		if rest != "" {
+			rs[len(rs)-1] = rs[len(rs)-1].(string) + rest
+		}
+		return f, rs
+	}
+	return phoneFormat{}, nil
+}
+
+// E164 returns number in E.164 format, eg. "+14155552671".
+func E164(region, number string) (string, error) {
+	n, err := parsePhone(region, number)
+	if err != nil {
+		return "", err
+	}
+	if !n.valid() {
+		return "", fmt.Errorf("invalid phone number %q", number)
+	}
+	return "+" + n.meta.code + n.nsn, nil
+}
+
+// IsValidPhone reports whether number is a valid phone number, in the
+// national format of region or in international format.
+func IsValidPhone(region, number string) bool {
+	n, err := parsePhone(region, number)
+	return err == nil && n.valid()
+}
+
+// FormatPhone formats number for display to someone in region: numbers
+// from the same region use the national format, "(415) 555-2671", and other
+// numbers the international format, "+44 20 7946 0018".
+func FormatPhone(region, number string) (string, error) {
+	n, err := parsePhone(region, number)
+	if err != nil {
+		return "", err
+	}
+	if !n.valid() {
+		return "", fmt.Errorf("invalid phone number %q", number)
+	}
+	f, groups := n.groups()
+	if n.region == strings.ToUpper(region) {
+		return fmt.Sprintf(f.national, groups...), nil
+	}
+	sep := n.meta.intlSep
+	if sep == "" {
+		sep = " "
+	}
+	parts := make([]string, len(groups))
+	for i, g := range groups {
+		parts[i] = g.(string)
+	}
+	return "+" + n.meta.code + " " + strings.Join(parts, sep), nil
+}
@@ -0,0 +1,163 @@
+package funcmaps
+
+import (
+	"container/list"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces text removed by Redact.
+const Redacted = "[REDACTED]"
+
+// Secret is a string that formats as Redacted, so that it is not leaked by
+// templates, logs or error messages. Use Reveal to get the value.
+type Secret string
+
+// String returns Redacted.
+func (s Secret) String() string { return Redacted }
+
+// GoString returns Redacted, for the %#v verb.
+func (s Secret) GoString() string { return Redacted }
+
+// Format writes Redacted, whatever the verb.
+func (s Secret) Format(f fmt.State, verb rune) { fmt.Fprint(f, Redacted) }
+
+// Reveal returns the secret value.
+func (s Secret) Reveal() string { return string(s) }
+
+// Privacy returns functions hiding personal data in rendered output.
+func Privacy() FuncMap {
+	return FuncMap{
+		"mask":      Mask,
+		"maskEmail": MaskEmail,
+		"maskCard":  MaskCard,
+		"redact":    Redact,
+	}
+}
+
+// Mask replaces every character of s with "*", except the last keepLast.
+func Mask(keepLast int, s string) string {
+	rs := []rune(s)
+	if keepLast < 0 {
+		keepLast = 0
+	}
+	for i := 0; i < len(rs)-keepLast; i++ {
+		rs[i] = '*'
+	}
+	return string(rs)
+}
+
+// MaskEmail masks the local part of an email address except its first
+// character, so "ada@example.com" gives "a**@example.com".
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return Mask(0, email)
+	}
+	local := []rune(email[:at])
+	if len(local) == 0 {
+		return email
+	}
+	return string(local[0]) + strings.Repeat("*", len(local)-1) + email[at:]
+}
+
+// MaskCard masks every digit of a card number except the last four,
+// keeping separators, so "4111 1111 1111 1111" gives "**** **** **** 1111".
+func MaskCard(number string) string {
+	digits := 0
+	for _, r := range number {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	rs := []rune(number)
+	for i, r := range rs {
+		if r >= '0' && r <= '9' {
+			if digits > 4 {
+				rs[i] = '*'
+			}
+			digits--
+		}
+	}
+	return string(rs)
+}
+
+var redactPatterns = newRegexpCache(regexpCacheSize)
+
+// Redact replaces every match of the regular expressions in patterns with
+// Redacted. patterns is a string or a list of strings.
+func Redact(patterns interface{}, s string) (string, error) {
+	var list []string
+	switch p := patterns.(type) {
+	case string:
+		list = []string{p}
+	case []string:
+		list = p
+	default:
+		values, err := listValues(patterns)
+		if err != nil {
+			return "", err
+		}
+		for _, v := range values {
+			list = append(list, stringifyValue(v))
+		}
+	}
+	for _, p := range list {
+		re, err := redactPatterns.compile(p)
+		if err != nil {
+			return "", err
+		}
+		s = re.ReplaceAllLiteralString(s, Redacted)
+	}
+	return s, nil
+}
+
+// regexpCacheSize is the number of patterns kept by the caches of Redact
+// and SeqGrep, whose patterns may come from data.
+const regexpCacheSize = 128
+
+// regexpCache keeps the most recently used of the regular expressions it
+// compiled.
+type regexpCache struct {
+	mu    sync.Mutex
+	max   int
+	order *list.List               // of *regexpEntry, most recently used first
+	items map[string]*list.Element // by pattern
+}
+
+type regexpEntry struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func newRegexpCache(max int) *regexpCache {
+	return &regexpCache{max: max, order: list.New(), items: map[string]*list.Element{}}
+}
+
+// compile compiles pattern, or returns it from the cache.
+func (c *regexpCache) compile(pattern string) (*regexp.Regexp, error) {
+	c.mu.Lock()
+	if e, ok := c.items[pattern]; ok {
+		c.order.MoveToFront(e)
+		c.mu.Unlock()
+		return e.Value.(*regexpEntry).re, nil
+	}
+	c.mu.Unlock()
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[pattern]; !ok {
+		c.items[pattern] = c.order.PushFront(&regexpEntry{pattern: pattern, re: re})
+		if c.order.Len() > c.max {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*regexpEntry).pattern)
+		}
+	}
+	return re, nil
+}
@@ -0,0 +1,37 @@
+package funcmaps
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRegexpCacheBounded(t *testing.T) {
+	c := newRegexpCache(4)
+	for i := 0; i < 10; i++ {
+		if _, err := c.compile(fmt.Sprintf("a%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(c.items) != 4 || c.order.Len() != 4 {
+		t.Fatalf("cache has %d patterns, want 4", len(c.items))
+	}
+	if _, ok := c.items["a9"]; !ok {
+		t.Error("the last pattern was evicted")
+	}
+	if _, ok := c.items["a0"]; ok {
+		t.Error("the first pattern was kept")
+	}
+	if _, err := c.compile("("); err == nil {
+		t.Error("compile of an invalid pattern: got nil error")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	got, err := Redact([]string{`\d{4}`, "secret"}, "pin 1234 is secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "pin " + Redacted + " is " + Redacted; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
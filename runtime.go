@@ -0,0 +1,41 @@
+package funcmaps
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// processStart approximates the start of the process, for uptime.
+var processStart = time.Now()
+
+// Runtime returns functions giving facts about the running process, for
+// status and diagnostic pages. They are not part of Default, since they
+// reveal details of the host.
+func Runtime() FuncMap {
+	return recordRisks(FuncMap{
+		"buildInfo": BuildInfo,
+		"goVersion": runtime.Version,
+		"hostname":  os.Hostname,
+		"pid":       os.Getpid,
+		"uptime":    Uptime,
+		"numCPU":    runtime.NumCPU,
+	})
+}
+
+// BuildInfo returns the build information embedded in the binary, such as
+// .Main.Version and .Deps, or nil when there is none.
+func BuildInfo() *debug.BuildInfo {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	return info
+}
+
+// Uptime returns how long the process has been running, truncated to
+// seconds. It is measured from the initialization of this package.
+func Uptime() time.Duration {
+	return time.Since(processStart).Truncate(time.Second)
+}
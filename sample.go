@@ -0,0 +1,141 @@
+package funcmaps
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxSample is the largest number of items returned by Sample.
+const MaxSample = 100
+
+// SampleUser is a user returned by Sample.
+type SampleUser struct {
+	ID       int
+	Name     string
+	Username string
+	Email    string
+	Bio      string
+	Admin    bool
+	Joined   time.Time
+}
+
+// SamplePost is a blog post returned by Sample.
+type SamplePost struct {
+	ID        int
+	Title     string
+	Slug      string
+	Summary   string
+	Body      string
+	Tags      []string
+	Author    SampleUser
+	Published time.Time
+}
+
+// SampleProduct is a product returned by Sample.
+type SampleProduct struct {
+	ID          int
+	SKU         string
+	Name        string
+	Description string
+	Price       float64
+	Currency    string
+	InStock     bool
+}
+
+// SampleData returns the sample function, giving canned data to render
+// templates with, in galleries and previews.
+func SampleData() FuncMap {
+	return FuncMap{
+		"sample": Sample,
+	}
+}
+
+var (
+	sampleFirst = []string{"Ada", "Grace", "Alan", "Edsger", "Barbara", "Donald", "Frances", "Ken", "Radia", "John"}
+	sampleLast  = []string{"Lovelace", "Hopper", "Turing", "Dijkstra", "Liskov", "Knuth", "Allen", "Thompson", "Perlman", "Backus"}
+	sampleTopic = []string{"Templates", "Caching", "Testing", "Deployment", "Accessibility", "Performance", "Security", "Logging", "Search", "Email"}
+	sampleKind  = []string{"A Gentle Introduction to", "Five Mistakes in", "Rethinking", "The Hidden Cost of", "Notes on", "Practical"}
+	sampleItem  = []string{"Mug", "Notebook", "Backpack", "Lamp", "Keyboard", "Water Bottle", "Headphones", "Desk Mat", "Pen Set", "Plant Pot"}
+	sampleColor = []string{"Blue", "Charcoal", "Olive", "Sand", "Red", "White"}
+	sampleEpoch = time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)
+)
+
+// Sample returns n items of canned data of the given kind: "users" as
+// []SampleUser, "posts" as []SamplePost or "products" as []SampleProduct.
+// The data is the same on every call, so that output can be compared.
+func Sample(kind string, n int) (interface{}, error) {
+	if n < 0 || n > MaxSample {
+		return nil, fmt.Errorf("sample: count %d out of range [0, %d]", n, MaxSample)
+	}
+	switch kind {
+	case "users":
+		users := make([]SampleUser, n)
+		for i := range users {
+			users[i] = sampleUser(i)
+		}
+		return users, nil
+	case "posts":
+		posts := make([]SamplePost, n)
+		for i := range posts {
+			posts[i] = samplePost(i)
+		}
+		return posts, nil
+	case "products":
+		products := make([]SampleProduct, n)
+		for i := range products {
+			products[i] = sampleProduct(i)
+		}
+		return products, nil
+	}
+	return nil, fmt.Errorf("sample: unknown kind %q, want users, posts or products", kind)
+}
+
+func sampleUser(i int) SampleUser {
+	first := sampleFirst[i%len(sampleFirst)]
+	last := sampleLast[(i+i/len(sampleFirst))%len(sampleLast)]
+	username := strings.ToLower(first[:1] + last)
+	if i >= len(sampleFirst) {
+		username += fmt.Sprint(i / len(sampleFirst))
+	}
+	return SampleUser{
+		ID:       i + 1,
+		Name:     first + " " + last,
+		Username: username,
+		Email:    username + "@example.com",
+		Bio:      fmt.Sprintf("%s writes about %s.", first, strings.ToLower(sampleTopic[i%len(sampleTopic)])),
+		Admin:    i == 0,
+		Joined:   sampleEpoch.AddDate(0, 0, 17*i),
+	}
+}
+
+func samplePost(i int) SamplePost {
+	topic := sampleTopic[i%len(sampleTopic)]
+	title := sampleKind[i%len(sampleKind)] + " " + topic
+	summary := fmt.Sprintf("What we learned about %s after a year in production.", strings.ToLower(topic))
+	return SamplePost{
+		ID:      i + 1,
+		Title:   title,
+		Slug:    strings.ReplaceAll(strings.ToLower(title), " ", "-"),
+		Summary: summary,
+		Body: summary + "\n\nMost of the work was not where we expected it. " +
+			"The first version was simple, and the second one was simpler still.",
+		Tags:      []string{strings.ToLower(topic), []string{"go", "web", "ops"}[i%3]},
+		Author:    sampleUser(i % 5),
+		Published: sampleEpoch.AddDate(0, 1, 7*i),
+	}
+}
+
+func sampleProduct(i int) SampleProduct {
+	item := sampleItem[i%len(sampleItem)]
+	color := sampleColor[(i+i/len(sampleItem))%len(sampleColor)]
+	return SampleProduct{
+		ID:          i + 1,
+		SKU:         fmt.Sprintf("SKU-%04d", 1000+i*7),
+		Name:        color + " " + item,
+		Description: fmt.Sprintf("A %s %s, built to last.", strings.ToLower(color), strings.ToLower(item)),
+		Price:       float64(500+(i*1337)%9500) / 100,
+		Currency:    "USD",
+		InStock:     i%4 != 3,
+	}
+}
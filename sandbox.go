@@ -0,0 +1,174 @@
+package funcmaps
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Sandbox limits, used unless changed with SandboxTimeout and
+// SandboxMaxOutput.
+const (
+	DefaultSandboxTimeout   = time.Second
+	DefaultSandboxMaxOutput = 1 << 20
+)
+
+var (
+	errSandboxTimeout = errors.New("sandbox: execution time limit exceeded")
+	errSandboxOutput  = errors.New("sandbox: output size limit exceeded")
+)
+
+// nonHermetic lists the functions of Default whose results depend on more
+// than their arguments. date and date_locale format values other than
+// times as the current time. newScratch is left out too: the methods of a
+// Scratch are not functions of the map, so Guard and Limit cannot bound
+// the values it grows.
+var nonHermetic = []string{"env", "now", "NOW", "uuid", "date", "date_locale", "newScratch"}
+
+// Hermetic returns the functions of Default whose results only depend on
+// their arguments, without the ones reading the environment, the clock or
+// random numbers.
+func Hermetic() FuncMap {
+	return Default(Without(nonHermetic...))
+}
+
+// Sandboxed parses and executes templates written by end users, such as
+// notification or page templates edited in an admin UI. Create it with
+// Sandbox.
+type Sandboxed struct {
+	funcs     FuncMap
+	timeout   time.Duration
+	maxOutput int
+}
+
+// SandboxOption configures Sandbox.
+type SandboxOption func(*Sandboxed)
+
+// SandboxFuncs adds the functions in fm. The functions of Trusted and
+// Debug are never available, even when included in fm.
+func SandboxFuncs(fm FuncMap) SandboxOption {
+	return func(s *Sandboxed) {
+		for name, f := range fm {
+			s.funcs[name] = f
+		}
+	}
+}
+
+// SandboxTimeout sets the execution time limit.
+func SandboxTimeout(d time.Duration) SandboxOption {
+	return func(s *Sandboxed) {
+		s.timeout = d
+	}
+}
+
+// SandboxMaxOutput sets the output size limit, in bytes.
+func SandboxMaxOutput(n int) SandboxOption {
+	return func(s *Sandboxed) {
+		s.maxOutput = n
+	}
+}
+
+// Sandbox returns a vetted configuration for rendering templates written
+// by end users, as html/template templates:
+//
+//   - only the Hermetic functions, and those added with SandboxFuncs,
+//     are available; the functions of Trusted and Debug never are,
+//   - missing map keys are errors,
+//   - the arguments of the functions are limited by DefaultGuards,
+//   - executions are limited in time and output size, as are the
+//     results of function calls, and
+//   - nothing is written unless the execution succeeds.
+//
+// The time limit is checked on every function call and write, so a loop
+// doing neither is not interrupted until it ends.
+func Sandbox(opts ...SandboxOption) *Sandboxed {
+	s := &Sandboxed{
+		funcs:     Hermetic(),
+		timeout:   DefaultSandboxTimeout,
+		maxOutput: DefaultSandboxMaxOutput,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	for name := range Combined(Trusted(), Debug()) {
+		delete(s.funcs, name)
+	}
+	s.funcs = Limit(Guard(s.funcs, DefaultGuards), LimitOptions{MaxOutputBytes: s.maxOutput})
+	return s
+}
+
+// SandboxTemplate is a template parsed by Sandboxed.
+type SandboxTemplate struct {
+	s      *Sandboxed
+	master *template.Template // never executed, so that it can be cloned
+}
+
+// Parse parses text as a template named name.
+func (s *Sandboxed) Parse(name, text string) (*SandboxTemplate, error) {
+	t, err := template.New(name).Option("missingkey=error").Funcs(template.FuncMap(s.funcs)).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &SandboxTemplate{s: s, master: t}, nil
+}
+
+// Execute applies the template to data, writing the output to w if the
+// execution succeeds within the limits.
+func (t *SandboxTemplate) Execute(w io.Writer, data interface{}) error {
+	c, err := t.master.Clone()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), t.s.timeout)
+	defer cancel()
+	c.Funcs(template.FuncMap(wrapFuncs(t.s.funcs, func(name string, args []reflect.Value, next func([]reflect.Value) []reflect.Value) []reflect.Value {
+		if ctx.Err() != nil {
+			panic(errSandboxTimeout)
+		}
+		return next(args)
+	})))
+
+	out := &contextWriter{ctx: ctx, max: t.s.maxOutput, errFull: errSandboxOutput}
+	err = executeContext(ctx, func() error {
+		return c.Execute(out, data)
+	})
+	if ctx.Err() != nil {
+		return errSandboxTimeout
+	}
+	if err != nil {
+		return sandboxError(err)
+	}
+	_, err = w.Write(out.buf.Bytes())
+	return err
+}
+
+// sandboxError returns the limit error err was caused by, if any.
+func sandboxError(err error) error {
+	for _, limit := range []error{errSandboxTimeout, errSandboxOutput} {
+		if errors.Is(err, limit) {
+			return err
+		}
+		// raised by a function call, and reported as its error
+		if strings.Contains(err.Error(), limit.Error()) {
+			return &sandboxLimitError{err: err, limit: limit}
+		}
+	}
+	// raised by Limit
+	if strings.Contains(err.Error(), ErrLimitExceeded.Error()) {
+		return &sandboxLimitError{err: err, limit: errSandboxOutput}
+	}
+	return err
+}
+
+// sandboxLimitError is an execution error caused by the sandbox limit.
+type sandboxLimitError struct {
+	err   error
+	limit error
+}
+
+func (e *sandboxLimitError) Error() string { return e.err.Error() }
+func (e *sandboxLimitError) Unwrap() error { return e.limit }
@@ -0,0 +1,92 @@
+package funcmaps
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSandboxNoScratch(t *testing.T) {
+	_, err := Sandbox().Parse("t", `{{$s := newScratch}}{{$s.Set "x" "ab"}}{{range seq 1 26}}{{$s.Add "x" ($s.Get "x")}}{{end}}{{len ($s.Get "x")}}`)
+	if err == nil || !strings.Contains(err.Error(), `"newScratch" not defined`) {
+		t.Fatalf("Parse: got error %v, want newScratch not defined", err)
+	}
+}
+
+func TestSandboxExecute(t *testing.T) {
+	st, err := Sandbox().Parse("t", `Hello, {{.Name | upper}}!`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := st.Execute(&buf, map[string]string{"Name": "gopher"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "Hello, GOPHER!"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSandboxOutputLimit(t *testing.T) {
+	st, err := Sandbox(SandboxMaxOutput(10)).Parse("t", `{{range .}}{{.}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err = st.Execute(&buf, make([]int, 100))
+	if !errors.Is(err, errSandboxOutput) {
+		t.Fatalf("got error %v, want %v", err, errSandboxOutput)
+	}
+	if strings.Count(err.Error(), errSandboxOutput.Error()) != 1 {
+		t.Errorf("error %q repeats %q", err, errSandboxOutput)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("wrote %q on error", buf.String())
+	}
+}
+
+func TestSandboxResultLimit(t *testing.T) {
+	st, err := Sandbox(SandboxMaxOutput(100)).Parse("t", `{{len (repeat 1000 "x")}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Execute(io.Discard, nil); !errors.Is(err, errSandboxOutput) {
+		t.Errorf("got error %v, want %v", err, errSandboxOutput)
+	}
+}
+
+func TestSandboxTimeout(t *testing.T) {
+	st, err := Sandbox(SandboxTimeout(time.Millisecond)).Parse("t", `{{range seq 1 10000}}{{range seq 1 10000}}{{upper "x"}}{{end}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Execute(io.Discard, nil); !errors.Is(err, errSandboxTimeout) {
+		t.Errorf("got error %v, want %v", err, errSandboxTimeout)
+	}
+}
+
+func TestSandboxError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{errSandboxTimeout, errSandboxTimeout},
+		{errSandboxOutput, errSandboxOutput},
+		{fmt.Errorf("template: t:1:2: error calling upper: %v", errSandboxTimeout), errSandboxTimeout},
+		{fmt.Errorf("template: t:1:2: error calling upper: %v", errSandboxOutput), errSandboxOutput},
+		{fmt.Errorf("template: t:1:2: error calling repeat: %v", ErrLimitExceeded), errSandboxOutput},
+	}
+	for _, tt := range tests {
+		got := sandboxError(tt.err)
+		if !errors.Is(got, tt.want) {
+			t.Errorf("sandboxError(%q) = %q, want %q", tt.err, got, tt.want)
+		}
+		if strings.Count(got.Error(), "limit exceeded") != 1 {
+			t.Errorf("sandboxError(%q) = %q, repeating the limit", tt.err, got)
+		}
+	}
+}
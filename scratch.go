@@ -0,0 +1,155 @@
+package funcmaps
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/spf13/cast"
+)
+
+// Scratch is a store of values that templates can change, created with the
+// newScratch function, for state shared between blocks and partials where
+// template variables are out of scope, as in Hugo:
+//
+//	{{$s := newScratch}}
+//	{{range .Items}}{{$s.Add "total" .Price}}{{end}}
+//	Total: {{$s.Get "total"}}
+//
+// The methods changing the store return an empty string, so that calling
+// them writes nothing. A Scratch is safe for concurrent use.
+type Scratch struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+}
+
+// NewScratch returns an empty Scratch.
+func NewScratch() *Scratch {
+	return &Scratch{values: map[string]interface{}{}}
+}
+
+// Set stores value for key.
+func (s *Scratch) Set(key string, value interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	return ""
+}
+
+// Get returns the value stored for key, or nil.
+func (s *Scratch) Get(key string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[key]
+}
+
+// Add adds value to the value stored for key: numbers are summed, strings
+// concatenated, and values appended to slices, or the elements of value if
+// it is a slice too. Without a stored value, value is stored.
+func (s *Scratch) Add(key string, value interface{}) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.values[key]
+	if !ok || old == nil {
+		s.values[key] = value
+		return "", nil
+	}
+	sum, err := addValues(old, value)
+	if err != nil {
+		return "", fmt.Errorf("scratch: %s: %v", key, err)
+	}
+	s.values[key] = sum
+	return "", nil
+}
+
+// addValues returns the sum, concatenation or append of a and b.
+func addValues(a, b interface{}) (interface{}, error) {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case av.Kind() == reflect.Slice:
+		if bv.Kind() == reflect.Slice {
+			rs, err := listValues(a)
+			if err != nil {
+				return nil, err
+			}
+			more, _ := listValues(b)
+			return append(rs, more...), nil
+		}
+		rs, _ := listValues(a)
+		return append(rs, b), nil
+	case av.Kind() == reflect.String && bv.Kind() == reflect.String:
+		return av.String() + bv.String(), nil
+	case numberKind(av) == 1 && numberKind(bv) == 1:
+		return cast.ToInt64(a) + cast.ToInt64(b), nil
+	case numberKind(av) > 0 && numberKind(bv) > 0:
+		return cast.ToFloat64(a) + cast.ToFloat64(b), nil
+	}
+	return nil, fmt.Errorf("cannot add %T to %T", b, a)
+}
+
+// numberKind returns 1 for integers, 2 for floating point numbers, or 0.
+func numberKind(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return 1
+	case reflect.Float32, reflect.Float64:
+		return 2
+	}
+	return 0
+}
+
+// SetInMap stores value for mapKey in the map stored for key, creating it
+// if needed.
+func (s *Scratch) SetInMap(key, mapKey string, value interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.values[key].(map[string]interface{})
+	if !ok {
+		m = map[string]interface{}{}
+		s.values[key] = m
+	}
+	m[mapKey] = value
+	return ""
+}
+
+// GetSortedMapValues returns the values of the map stored for key with
+// SetInMap, sorted by their keys, or nil.
+func (s *Scratch) GetSortedMapValues(key string) []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.values[key].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	rs := make([]interface{}, len(keys))
+	for i, k := range keys {
+		rs[i] = m[k]
+	}
+	return rs
+}
+
+// Delete removes the value stored for key.
+func (s *Scratch) Delete(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	return ""
+}
+
+// Values returns a copy of the stored values.
+func (s *Scratch) Values() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs := make(map[string]interface{}, len(s.values))
+	for k, v := range s.values {
+		rs[k] = v
+	}
+	return rs
+}
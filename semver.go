@@ -0,0 +1,286 @@
+package funcmaps
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Semver returns functions comparing semantic versions, such as "v1.2.3"
+// or "2.0.0-rc.1", for release notes and compatibility matrices.
+func Semver() FuncMap {
+	return FuncMap{
+		"semverCompare": SemverCompare,
+		"semverMajor":   func(v string) (int, error) { return semverPart(v, 0) },
+		"semverMinor":   func(v string) (int, error) { return semverPart(v, 1) },
+		"semverPatch":   func(v string) (int, error) { return semverPart(v, 2) },
+		"semverSort":    SemverSort,
+	}
+}
+
+// Version is a parsed semantic version.
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          string
+	Build               string
+}
+
+var semverRe = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+
+// ParseVersion parses a semantic version. The "v" prefix is optional, and
+// a missing minor or patch version is zero.
+func ParseVersion(s string) (Version, error) {
+	v, _, err := parseVersion(s)
+	return v, err
+}
+
+// parseVersion parses s, also returning the number of numeric parts given.
+func parseVersion(s string) (Version, int, error) {
+	m := semverRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+	var v Version
+	parts := 0
+	for i, p := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		if m[i+1] == "" {
+			break
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return Version{}, 0, fmt.Errorf("invalid version %q", s)
+		}
+		*p = n
+		parts++
+	}
+	v.Prerelease, v.Build = m[4], m[5]
+	return v, parts, nil
+}
+
+// String returns the version without a "v" prefix.
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare returns -1, 0 or +1 as v has lower, equal or higher precedence
+// than w. Build metadata is ignored.
+func (v Version) Compare(w Version) int {
+	for _, d := range []int{v.Major - w.Major, v.Minor - w.Minor, v.Patch - w.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	return comparePrerelease(v.Prerelease, w.Prerelease)
+}
+
+func sign(d int) int {
+	switch {
+	case d < 0:
+		return -1
+	case d > 0:
+		return 1
+	}
+	return 0
+}
+
+// comparePrerelease compares prerelease versions as specified by SemVer:
+// a release has higher precedence than its prereleases, and identifiers
+// are compared numerically when both are numbers.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aerr := strconv.Atoi(as[i])
+		bn, berr := strconv.Atoi(bs[i])
+		switch {
+		case aerr == nil && berr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aerr == nil:
+			return -1
+		case berr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+func semverPart(s string, i int) (int, error) {
+	v, err := ParseVersion(s)
+	if err != nil {
+		return 0, err
+	}
+	return []int{v.Major, v.Minor, v.Patch}[i], nil
+}
+
+// SemverSort returns the versions of list sorted in ascending order of
+// precedence.
+func SemverSort(list interface{}) ([]string, error) {
+	values, err := listValues(list)
+	if err != nil {
+		return nil, err
+	}
+	type entry struct {
+		s string
+		v Version
+	}
+	entries := make([]entry, len(values))
+	for i, val := range values {
+		s, err := toText(val)
+		if err != nil {
+			return nil, err
+		}
+		v, err := ParseVersion(s)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entry{s, v}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].v.Compare(entries[j].v) < 0 })
+	rs := make([]string, len(entries))
+	for i, e := range entries {
+		rs[i] = e.s
+	}
+	return rs, nil
+}
+
+// comparator tests a version against a bound.
+type comparator struct {
+	op string
+	v  Version
+}
+
+func (c comparator) match(v Version) bool {
+	d := v.Compare(c.v)
+	switch c.op {
+	case "=":
+		return d == 0
+	case "!=":
+		return d != 0
+	case ">":
+		return d > 0
+	case ">=":
+		return d >= 0
+	case "<":
+		return d < 0
+	}
+	return d <= 0 // "<="
+}
+
+var comparatorRe = regexp.MustCompile(`^(=|!=|>=|<=|>|<|~|\^)?\s*(.*)$`)
+
+// SemverCompare reports whether version satisfies constraint.
+//
+// A constraint is a list of alternatives separated by "||", each a list of
+// comparisons separated by spaces or commas, all of which must hold:
+// "=1.2.3", "!=1.2.3", ">1.2", ">=1.2", "<2", "<=2.1", and the ranges
+// "1.2.x" or "1.2" (>=1.2.0 <1.3.0), "~1.2.3" (>=1.2.3 <1.3.0), and
+// "^1.2.3" (>=1.2.3 <2.0.0; ^0.2.3 is <0.3.0). "*" matches any version.
+func SemverCompare(constraint, version string) (bool, error) {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	for _, alt := range strings.Split(constraint, "||") {
+		comps, err := parseComparators(alt)
+		if err != nil {
+			return false, err
+		}
+		ok := true
+		for _, c := range comps {
+			if !c.match(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func parseComparators(s string) ([]comparator, error) {
+	var rs []comparator
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+	// join operators separated from their version, as in ">= 1.2"
+	for i := 0; i < len(fields); i++ {
+		if strings.Trim(fields[i], "=!<>~^") == "" && i+1 < len(fields) {
+			fields[i+1] = fields[i] + fields[i+1]
+			continue
+		}
+		cs, err := parseComparator(fields[i])
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, cs...)
+	}
+	return rs, nil
+}
+
+func parseComparator(s string) ([]comparator, error) {
+	m := comparatorRe.FindStringSubmatch(s)
+	op, vs := m[1], m[2]
+	for strings.HasSuffix(vs, ".x") || strings.HasSuffix(vs, ".X") || strings.HasSuffix(vs, ".*") {
+		vs = vs[:len(vs)-2]
+	}
+	if vs == "*" || vs == "x" || vs == "X" {
+		return nil, nil
+	}
+	v, parts, err := parseVersion(vs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid constraint %q", s)
+	}
+	// upper bound of the range of versions starting with the given parts
+	next := func(parts int) Version {
+		switch parts {
+		case 1:
+			return Version{Major: v.Major + 1}
+		case 2:
+			return Version{Major: v.Major, Minor: v.Minor + 1}
+		}
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+	switch op {
+	case "", "=":
+		if parts == 3 {
+			return []comparator{{"=", v}}, nil
+		}
+		return []comparator{{">=", v}, {"<", next(parts)}}, nil
+	case "~":
+		if parts == 1 {
+			return []comparator{{">=", v}, {"<", next(1)}}, nil
+		}
+		return []comparator{{">=", v}, {"<", next(2)}}, nil
+	case "^":
+		switch {
+		case v.Major > 0 || parts == 1:
+			return []comparator{{">=", v}, {"<", next(1)}}, nil
+		case v.Minor > 0 || parts == 2:
+			return []comparator{{">=", v}, {"<", next(2)}}, nil
+		}
+		return []comparator{{">=", v}, {"<", next(3)}}, nil
+	}
+	return []comparator{{op, v}}, nil
+}
@@ -0,0 +1,147 @@
+package funcmaps
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"time"
+)
+
+// SEO returns functions generating structured data for search engines:
+//
+//	{{breadcrumbJSONLD "Home" "https://a.com/" "Docs" "https://a.com/docs/"}}
+//	{{breadcrumbJSONLD .Crumbs}}
+//	{{sitemapURL "https://a.com/docs/" .Updated "weekly"}}
+//
+// breadcrumbJSONLD takes pairs of names and URLs, or a list of maps or
+// structs with Name and URL keys, and returns a script element of a
+// schema.org BreadcrumbList. sitemapURL returns the url element of a
+// sitemap; the last modification time and change frequency are omitted
+// when empty.
+func SEO() FuncMap {
+	return FuncMap{
+		"breadcrumbJSONLD": BreadcrumbJSONLD,
+		"sitemapURL":       SitemapURL,
+	}
+}
+
+// Breadcrumb is an item of a breadcrumb trail.
+type Breadcrumb struct {
+	Name string
+	URL  string
+}
+
+// BreadcrumbJSONLD returns a script element holding the schema.org
+// BreadcrumbList of the given crumbs: pairs of names and URLs, or a single
+// list of Breadcrumb values, or of maps or structs with Name and URL keys.
+func BreadcrumbJSONLD(crumbs ...interface{}) (template.HTML, error) {
+	list, err := breadcrumbs(crumbs)
+	if err != nil {
+		return "", fmt.Errorf("breadcrumbJSONLD: %v", err)
+	}
+	type listItem struct {
+		Type     string `json:"@type"`
+		Position int    `json:"position"`
+		Name     string `json:"name"`
+		Item     string `json:"item,omitempty"`
+	}
+	items := make([]listItem, len(list))
+	for i, c := range list {
+		items[i] = listItem{Type: "ListItem", Position: i + 1, Name: c.Name, Item: c.URL}
+	}
+	// json.Marshal escapes <, > and &, so the script element cannot be closed
+	b, err := json.Marshal(struct {
+		Context string     `json:"@context"`
+		Type    string     `json:"@type"`
+		Items   []listItem `json:"itemListElement"`
+	}{"https://schema.org", "BreadcrumbList", items})
+	if err != nil {
+		return "", err
+	}
+	return template.HTML(`<script type="application/ld+json">` + string(b) + `</script>`), nil
+}
+
+func breadcrumbs(args []interface{}) ([]Breadcrumb, error) {
+	if len(args) == 1 && isList(args[0]) {
+		if bs, ok := args[0].([]Breadcrumb); ok {
+			return bs, nil
+		}
+		values, err := listValues(args[0])
+		if err != nil {
+			return nil, err
+		}
+		rs := make([]Breadcrumb, len(values))
+		for i, v := range values {
+			if b, ok := v.(Breadcrumb); ok {
+				rs[i] = b
+				continue
+			}
+			name, ok := keyValue(v, "Name")
+			if !ok {
+				name, ok = keyValue(v, "name")
+			}
+			if !ok {
+				return nil, fmt.Errorf("crumb %d has no Name", i)
+			}
+			u, ok := keyValue(v, "URL")
+			if !ok {
+				u, _ = keyValue(v, "url")
+			}
+			rs[i] = Breadcrumb{Name: stringifyValue(name)}
+			if u != nil {
+				rs[i].URL = stringifyValue(u)
+			}
+		}
+		return rs, nil
+	}
+	if len(args)%2 != 0 {
+		return nil, fmt.Errorf("expected pairs of names and URLs, got %d arguments", len(args))
+	}
+	rs := make([]Breadcrumb, 0, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		rs = append(rs, Breadcrumb{Name: stringifyValue(args[i]), URL: stringifyValue(args[i+1])})
+	}
+	return rs, nil
+}
+
+// changeFreqs are the values of the changefreq element of sitemaps.
+var changeFreqs = map[string]bool{
+	"always": true, "hourly": true, "daily": true, "weekly": true,
+	"monthly": true, "yearly": true, "never": true,
+}
+
+// SitemapURL returns the url element of a sitemap for loc. lastmod is a
+// time, as accepted by FormatTime, or a string in W3C date format, such as
+// "2024-05-01"; it is omitted if nil, empty or neither, as is an empty
+// changefreq.
+func SitemapURL(loc string, lastmod interface{}, changefreq string) (template.HTML, error) {
+	if changefreq != "" && !changeFreqs[changefreq] {
+		return "", fmt.Errorf("sitemapURL: invalid changefreq %q", changefreq)
+	}
+	var mod string
+	switch v := lastmod.(type) {
+	case nil:
+	case string:
+		mod = v
+	default:
+		if t, ok := toTime(v); ok && !t.IsZero() {
+			mod = t.Format(time.RFC3339)
+		}
+	}
+	var b bytes.Buffer
+	b.WriteString("<url><loc>")
+	xml.EscapeText(&b, []byte(loc))
+	b.WriteString("</loc>")
+	if mod != "" {
+		b.WriteString("<lastmod>")
+		xml.EscapeText(&b, []byte(mod))
+		b.WriteString("</lastmod>")
+	}
+	if changefreq != "" {
+		b.WriteString("<changefreq>" + changefreq + "</changefreq>")
+	}
+	b.WriteString("</url>")
+	return template.HTML(b.String()), nil
+}
@@ -0,0 +1,25 @@
+package funcmaps
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSitemapURLLastmod(t *testing.T) {
+	for _, tt := range []struct {
+		lastmod interface{}
+		want    string
+	}{
+		{time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "<url><loc>/a</loc><lastmod>2024-05-01T00:00:00Z</lastmod></url>"},
+		{"2024-05-01", "<url><loc>/a</loc><lastmod>2024-05-01</lastmod></url>"},
+		{nil, "<url><loc>/a</loc></url>"},
+		{time.Time{}, "<url><loc>/a</loc></url>"},
+		{(*time.Time)(nil), "<url><loc>/a</loc></url>"},
+		{3.5, "<url><loc>/a</loc></url>"},
+	} {
+		got, err := SitemapURL("/a", tt.lastmod, "")
+		if err != nil || string(got) != tt.want {
+			t.Errorf("SitemapURL with %#v = %q, %v, want %q", tt.lastmod, got, err, tt.want)
+		}
+	}
+}
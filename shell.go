@@ -0,0 +1,89 @@
+package funcmaps
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Shell returns functions quoting values for POSIX shell scripts, such as
+// those generated for Dockerfiles and CI jobs.
+func Shell() FuncMap {
+	return FuncMap{
+		"shquote":   ShellQuote,
+		"shjoin":    ShellJoin,
+		"envExport": EnvExport,
+	}
+}
+
+var (
+	shellSafeRe = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+	envNameRe   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// ShellQuote quotes s as a single shell word. Words made only of safe
+// characters are returned as they are; others are single quoted, with
+// single quotes written as '"'"'.
+func ShellQuote(s string) string {
+	if shellSafeRe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// ShellJoin quotes each of args with ShellQuote and joins them with spaces,
+// forming a command line.
+func ShellJoin(args interface{}) (string, error) {
+	values, err := listValues(args)
+	if err != nil {
+		return "", err
+	}
+	words := make([]string, len(values))
+	for i, v := range values {
+		s, err := toText(v)
+		if err != nil {
+			return "", err
+		}
+		words[i] = ShellQuote(s)
+	}
+	return strings.Join(words, " "), nil
+}
+
+// EnvExport returns an export statement for each entry of the map env, in
+// order of name, one per line: export NAME='value'. Names must be valid
+// shell variable names.
+func EnvExport(env interface{}) (string, error) {
+	v, isNil := indirect(reflect.ValueOf(env))
+	if isNil || !v.IsValid() {
+		return "", nil
+	}
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return "", fmt.Errorf("envExport: expected map with string keys, got %s", v.Type())
+	}
+	names := make([]string, 0, v.Len())
+	values := make(map[string]string, v.Len())
+	r := v.MapRange()
+	for r.Next() {
+		name := r.Key().String()
+		if !envNameRe.MatchString(name) {
+			return "", fmt.Errorf("envExport: invalid variable name %q", name)
+		}
+		s, err := toText(r.Value().Interface())
+		if err != nil {
+			return "", err
+		}
+		names = append(names, name)
+		values[name] = s
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString("export " + name + "=" + ShellQuote(values[name]))
+	}
+	return b.String(), nil
+}
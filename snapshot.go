@@ -0,0 +1,97 @@
+package funcmaps
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// FuncSnapshot maps the names of the functions of a FuncMap to their
+// signatures, such as "func(string, ...interface {}) (string, error)".
+// It encodes as JSON with sorted keys, to be kept with an application's
+// tests and compared with CompareSnapshots after upgrades.
+type FuncSnapshot map[string]string
+
+// Snapshot returns the names and signatures of the functions of fms, or of
+// All when no FuncMap is given.
+func Snapshot(fms ...FuncMap) FuncSnapshot {
+	if len(fms) == 0 {
+		fms = []FuncMap{All()}
+	}
+	s := FuncSnapshot{}
+	for _, fm := range fms {
+		for name, fn := range fm {
+			s[name] = signature(fn)
+		}
+	}
+	return s
+}
+
+// signature returns the type of fn as a string, with interface{} and any
+// written the same way, as reflect does.
+func signature(fn interface{}) string {
+	if fn == nil {
+		return "nil"
+	}
+	return reflect.TypeOf(fn).String()
+}
+
+// ReadSnapshot decodes a FuncSnapshot written by WriteTo.
+func ReadSnapshot(r io.Reader) (FuncSnapshot, error) {
+	var s FuncSnapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+	return s, nil
+}
+
+// WriteTo writes s to w as indented JSON.
+func (s FuncSnapshot) WriteTo(w io.Writer) (int64, error) {
+	b, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(b, '\n'))
+	return int64(n), err
+}
+
+// SnapshotChange is a function added, removed or re-typed between two
+// snapshots. Old is empty for added functions, New for removed ones.
+type SnapshotChange struct {
+	Name     string
+	Old, New string
+}
+
+// String returns the change as a line of a diff: "+ name sig" for added
+// functions, "- name sig" for removed ones and "~ name old => new" for
+// re-typed ones.
+func (c SnapshotChange) String() string {
+	switch {
+	case c.Old == "":
+		return "+ " + c.Name + " " + c.New
+	case c.New == "":
+		return "- " + c.Name + " " + c.Old
+	}
+	return "~ " + c.Name + " " + c.Old + " => " + c.New
+}
+
+// CompareSnapshots returns the changes from old to new, sorted by name.
+func CompareSnapshots(old, new FuncSnapshot) []SnapshotChange {
+	var changes []SnapshotChange
+	for name, sig := range old {
+		if n, ok := new[name]; !ok {
+			changes = append(changes, SnapshotChange{Name: name, Old: sig})
+		} else if n != sig {
+			changes = append(changes, SnapshotChange{Name: name, Old: sig, New: n})
+		}
+	}
+	for name, sig := range new {
+		if _, ok := old[name]; !ok {
+			changes = append(changes, SnapshotChange{Name: name, New: sig})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
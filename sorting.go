@@ -0,0 +1,106 @@
+package funcmaps
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// SortNatural returns the values of list sorted by their string
+// representation, comparing runs of digits by their numeric value, so that
+// "v2" sorts before "v10" and "file9.txt" before "file10.txt".
+func SortNatural(list interface{}) ([]interface{}, error) {
+	values, keys, err := sortKeys(list)
+	if err != nil {
+		return nil, err
+	}
+	sort.Stable(keyedValues{values, keys, func(a, b string) bool { return naturalLess(a, b) }})
+	return values, nil
+}
+
+// SortLocale returns the values of list sorted by their string
+// representation with the collation rules of the language lang, a BCP 47
+// tag such as "de" or "sv", so that "Ärger" sorts with "Arm" in German but
+// after "Zebra" in Swedish.
+func SortLocale(lang string, list interface{}) ([]interface{}, error) {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return nil, fmt.Errorf("sortLocale: invalid language %q", lang)
+	}
+	values, keys, err := sortKeys(list)
+	if err != nil {
+		return nil, err
+	}
+	c := collate.New(tag)
+	sort.Stable(keyedValues{values, keys, func(a, b string) bool { return c.CompareString(a, b) < 0 }})
+	return values, nil
+}
+
+// sortKeys returns the values of list and their string representations.
+func sortKeys(list interface{}) ([]interface{}, []string, error) {
+	values, err := listValues(list)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := make([]string, len(values))
+	for i, v := range values {
+		keys[i] = stringify(reflect.ValueOf(v))
+	}
+	return values, keys, nil
+}
+
+// keyedValues sorts values by their keys.
+type keyedValues struct {
+	values []interface{}
+	keys   []string
+	less   func(a, b string) bool
+}
+
+func (s keyedValues) Len() int           { return len(s.values) }
+func (s keyedValues) Less(i, j int) bool { return s.less(s.keys[i], s.keys[j]) }
+func (s keyedValues) Swap(i, j int) {
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+// naturalLess compares a and b by code point, except for runs of ASCII
+// digits, which are compared by numeric value, and then by length so that
+// "01" sorts after "1".
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		da, db := digitRun(a), digitRun(b)
+		if da > 0 && db > 0 {
+			na, nb := strings.TrimLeft(a[:da], "0"), strings.TrimLeft(b[:db], "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			if da != db {
+				return da < db
+			}
+			a, b = a[da:], b[db:]
+			continue
+		}
+		// comparing UTF-8 bytes orders runes by code point
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+// digitRun returns the length of the run of ASCII digits at the start of s.
+func digitRun(s string) int {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return i
+}
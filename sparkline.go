@@ -0,0 +1,122 @@
+package funcmaps
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// sparkBlocks are the levels of a sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// histogramWidth is the length of the longest bar of a histogram.
+const histogramWidth = 30
+
+// Sparkline returns the numbers of list as a line of block characters, one
+// per number, scaled from the smallest to the largest, such as "▁▃▅█▂" for
+// a monitoring email or chat message.
+func Sparkline(list interface{}) (string, error) {
+	xs, err := numbers(list)
+	if err != nil || len(xs) == 0 {
+		return "", err
+	}
+	lo, hi := bounds(xs)
+	line := make([]rune, len(xs))
+	for i, x := range xs {
+		level := 0
+		if hi > lo {
+			level = clampIndex((x-lo)/(hi-lo)*float64(len(sparkBlocks)-1), len(sparkBlocks))
+		}
+		line[i] = sparkBlocks[level]
+	}
+	return string(line), nil
+}
+
+// Histogram returns the distribution of the numbers in list as lines of
+// text, one per bucket of equal width from the smallest to the largest
+// number, with a bar and the count of numbers in the bucket:
+//
+//	  1–2.75 │██████████████████████████████ 4
+//	2.75–4.5 │███████████████ 2
+//	4.5–6.25 │███████▌ 1
+//	  6.25–8 │███████▌ 1
+func Histogram(buckets int, list interface{}) (string, error) {
+	if buckets < 1 {
+		return "", fmt.Errorf("invalid number of buckets %d", buckets)
+	}
+	xs, err := numbers(list)
+	if err != nil || len(xs) == 0 {
+		return "", err
+	}
+	lo, hi := bounds(xs)
+	width := (hi - lo) / float64(buckets)
+	counts := make([]int, buckets)
+	for _, x := range xs {
+		i := buckets - 1
+		if width > 0 {
+			i = clampIndex((x-lo)/width, buckets)
+		}
+		counts[i]++
+	}
+	max := 0
+	labels := make([]string, buckets)
+	labelWidth := 0
+	for i, n := range counts {
+		if n > max {
+			max = n
+		}
+		labels[i] = formatFloat(lo+float64(i)*width) + "–" + formatFloat(lo+float64(i+1)*width)
+		if n := len([]rune(labels[i])); n > labelWidth {
+			labelWidth = n
+		}
+	}
+	var b strings.Builder
+	for i, n := range counts {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(strings.Repeat(" ", labelWidth-len([]rune(labels[i]))))
+		b.WriteString(labels[i])
+		b.WriteString(" │")
+		b.WriteString(bar(float64(n) / float64(max) * histogramWidth))
+		b.WriteString(" " + strconv.Itoa(n))
+	}
+	return b.String(), nil
+}
+
+// clampIndex returns f truncated to an index of a slice of length n. Out
+// of range values, which differences of numbers near the float64 limits
+// may give, are the nearest index, and NaN is 0.
+func clampIndex(f float64, n int) int {
+	switch {
+	case !(f > 0):
+		return 0
+	case f >= float64(n-1):
+		return n - 1
+	}
+	return int(f)
+}
+
+// bar returns a horizontal bar of length n characters, in eighths.
+func bar(n float64) string {
+	eighths := int(math.Round(n * 8))
+	s := strings.Repeat("█", eighths/8)
+	if rem := eighths % 8; rem > 0 {
+		s += string([]rune("▏▎▍▌▋▊▉")[rem-1])
+	}
+	return s
+}
+
+// bounds returns the smallest and largest of xs, which is not empty.
+func bounds(xs []float64) (lo, hi float64) {
+	lo, hi = xs[0], xs[0]
+	for _, x := range xs[1:] {
+		lo, hi = math.Min(lo, x), math.Max(hi, x)
+	}
+	return lo, hi
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', 4, 64)
+}
@@ -0,0 +1,53 @@
+package funcmaps
+
+import (
+	"reflect"
+	"sort"
+)
+
+// FromSprig returns the functions of a FuncMap of another library, such
+// as sprig.FuncMap() or sprig.TxtFuncMap() from github.com/Masterminds/sprig,
+// as a FuncMap usable with Combined:
+//
+//	fm := funcmaps.Combined(funcmaps.FromSprig(sprig.FuncMap()), funcmaps.Default())
+//
+// Combined lets later maps win, so Default takes precedence above. Use
+// SprigConflicts to list the functions this shadows.
+func FromSprig(fm map[string]interface{}) FuncMap {
+	rs := make(FuncMap, len(fm))
+	for name, f := range fm {
+		rs[name] = f
+	}
+	return rs
+}
+
+// Conflict is a function name defined by two FuncMaps.
+type Conflict struct {
+	Name  string
+	Base  reflect.Type // type of the function in the base map
+	Other reflect.Type // type of the function in the other map
+}
+
+// SameType reports whether both functions have the same type, in which
+// case they can often be swapped without changing templates.
+func (c Conflict) SameType() bool {
+	return c.Base == c.Other
+}
+
+// Conflicts returns the names defined by both base and other, in order.
+func Conflicts(base, other FuncMap) []Conflict {
+	var rs []Conflict
+	for name, f := range other {
+		if g, ok := base[name]; ok {
+			rs = append(rs, Conflict{Name: name, Base: reflect.TypeOf(g), Other: reflect.TypeOf(f)})
+		}
+	}
+	sort.Slice(rs, func(i, j int) bool { return rs[i].Name < rs[j].Name })
+	return rs
+}
+
+// SprigConflicts returns the functions of a sprig FuncMap whose names are
+// also defined by Default.
+func SprigConflicts(sprig map[string]interface{}) []Conflict {
+	return Conflicts(Default(), FromSprig(sprig))
+}
@@ -0,0 +1,173 @@
+package funcmaps
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SQL returns functions quoting identifiers and literals for SQL generated
+// by templates, such as schema migrations, using standard SQL quoting as
+// understood by PostgreSQL and SQLite. Use SQLDialect for MySQL.
+//
+//	SELECT * FROM {{sqlIdent "public" "users"}}
+//	WHERE name = {{sqlString .Name}} AND id IN {{sqlIn .IDs}}
+//
+// Values that cannot be quoted safely, such as strings containing NUL
+// bytes or invalid UTF-8, are errors. Quoting is no substitute for query
+// parameters in queries executed by a program.
+func SQL() FuncMap {
+	fm, _ := SQLDialect("")
+	return fm
+}
+
+// SQLDialect returns the SQL functions quoting for dialect: "postgres",
+// "mysql", "sqlite", or "" for standard SQL.
+func SQLDialect(dialect string) (FuncMap, error) {
+	d, err := sqlDialectNamed(dialect)
+	if err != nil {
+		return nil, err
+	}
+	return FuncMap{
+		"sqlIdent":  d.ident,
+		"sqlString": d.str,
+		"sqlIn":     d.in,
+	}, nil
+}
+
+// QuoteIdent quotes the parts of a possibly qualified identifier, such as
+// a schema and a table name, for dialect.
+func QuoteIdent(dialect string, parts ...string) (string, error) {
+	d, err := sqlDialectNamed(dialect)
+	if err != nil {
+		return "", err
+	}
+	return d.ident(parts...)
+}
+
+// QuoteString quotes s as a string literal for dialect.
+func QuoteString(dialect string, s string) (string, error) {
+	d, err := sqlDialectNamed(dialect)
+	if err != nil {
+		return "", err
+	}
+	return d.str(s)
+}
+
+type sqlDialect struct {
+	identQuote byte
+	backslash  bool // backslashes in string literals are escapes
+}
+
+var sqlDialects = map[string]sqlDialect{
+	"":           {identQuote: '"'},
+	"ansi":       {identQuote: '"'},
+	"postgres":   {identQuote: '"'},
+	"postgresql": {identQuote: '"'},
+	"sqlite":     {identQuote: '"'},
+	"sqlite3":    {identQuote: '"'},
+	"mysql":      {identQuote: '`', backslash: true},
+	"mariadb":    {identQuote: '`', backslash: true},
+}
+
+func sqlDialectNamed(name string) (sqlDialect, error) {
+	d, ok := sqlDialects[strings.ToLower(name)]
+	if !ok {
+		return sqlDialect{}, fmt.Errorf("unknown SQL dialect %q", name)
+	}
+	return d, nil
+}
+
+// checkSQLText rejects text that cannot be quoted safely.
+func checkSQLText(what, s string) error {
+	if !utf8.ValidString(s) {
+		return fmt.Errorf("%s %q: invalid UTF-8", what, s)
+	}
+	if strings.IndexByte(s, 0) >= 0 {
+		return fmt.Errorf("%s %q: contains a NUL byte", what, s)
+	}
+	return nil
+}
+
+func (d sqlDialect) ident(parts ...string) (string, error) {
+	if len(parts) == 0 {
+		return "", fmt.Errorf("sqlIdent: missing identifier")
+	}
+	q := string(d.identQuote)
+	quoted := make([]string, len(parts))
+	for i, p := range parts {
+		if p == "" {
+			return "", fmt.Errorf("sqlIdent: empty identifier")
+		}
+		if err := checkSQLText("sqlIdent: identifier", p); err != nil {
+			return "", err
+		}
+		quoted[i] = q + strings.ReplaceAll(p, q, q+q) + q
+	}
+	return strings.Join(quoted, "."), nil
+}
+
+func (d sqlDialect) str(s string) (string, error) {
+	if err := checkSQLText("sqlString: string", s); err != nil {
+		return "", err
+	}
+	if d.backslash {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'", nil
+}
+
+// literal returns v as an SQL literal: NULL, TRUE or FALSE, a number, or a
+// quoted string or timestamp.
+func (d sqlDialect) literal(v interface{}) (string, error) {
+	rv, isNil := indirect(reflect.ValueOf(v))
+	if isNil || !rv.IsValid() {
+		return "NULL", nil
+	}
+	if t, ok := rv.Interface().(time.Time); ok {
+		return d.str(t.Format(time.RFC3339Nano))
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", fmt.Errorf("sqlIn: cannot quote %v", f)
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	case reflect.String:
+		return d.str(rv.String())
+	}
+	return "", fmt.Errorf("sqlIn: cannot quote value of type %s", rv.Type())
+}
+
+// in returns the values of list as a parenthesized list of literals, for
+// an IN clause. The list must not be empty, since "IN ()" is invalid.
+func (d sqlDialect) in(list interface{}) (string, error) {
+	values, err := listValues(list)
+	if err != nil {
+		return "", fmt.Errorf("sqlIn: %w", err)
+	}
+	if len(values) == 0 {
+		return "", fmt.Errorf("sqlIn: empty list")
+	}
+	lits := make([]string, len(values))
+	for i, v := range values {
+		if lits[i], err = d.literal(v); err != nil {
+			return "", err
+		}
+	}
+	return "(" + strings.Join(lits, ", ") + ")", nil
+}
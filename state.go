@@ -0,0 +1,84 @@
+package funcmaps
+
+import (
+	"html/template"
+	"sync"
+)
+
+// ExecState holds state scoped to a single template execution, such as the
+// position of a cycle or the content collected for a layout. It is safe for
+// concurrent use.
+//
+// Functions needing state are created by a StateFuncs, which Templates calls
+// with a new ExecState for every execution. Outside of Templates, create an
+// ExecState per execution and add the functions to a clone of the template:
+//
+//	st := funcmaps.NewExecState()
+//	c, _ := t.Clone()
+//	c.Funcs(template.FuncMap(myStateFuncs(st))).Execute(w, data)
+type ExecState struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+}
+
+// StateFuncs returns functions using st. Keys should be prefixed with the
+// name of the package or feature owning them, to avoid collisions.
+type StateFuncs func(st *ExecState) FuncMap
+
+// NewExecState returns an empty ExecState.
+func NewExecState() *ExecState {
+	return &ExecState{values: map[string]interface{}{}}
+}
+
+// Get returns the value stored for key.
+func (s *ExecState) Get(key string) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Set stores v for key.
+func (s *ExecState) Set(key string, v interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = v
+}
+
+// Delete removes the value stored for key.
+func (s *ExecState) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// Update atomically replaces the value stored for key with the result of
+// fn, which is given the current value, if any. It returns the new value.
+func (s *ExecState) Update(key string, fn func(v interface{}, ok bool) interface{}) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[key]
+	v = fn(v, ok)
+	s.values[key] = v
+	return v
+}
+
+// WithState adds functions created per execution by each of fns, with a
+// new ExecState shared by everything executed by that call, including the
+// layout and partials of Render.
+func WithState(fns ...StateFuncs) Option {
+	return func(t *Templates) {
+		t.state = append(t.state, fns...)
+	}
+}
+
+// stateFuncs returns the functions of fns bound to st.
+func stateFuncs(fns []StateFuncs, st *ExecState) template.FuncMap {
+	fm := template.FuncMap{}
+	for _, fn := range fns {
+		for name, f := range fn(st) {
+			fm[name] = f
+		}
+	}
+	return fm
+}
@@ -0,0 +1,171 @@
+package funcmaps
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Statistics returns functions computing statistics of the numbers in a
+// slice, array or map, for report templates:
+//
+//	sum .Totals               => 60
+//	avg .Totals               => 20 (also mean)
+//	median .Totals            => 15
+//	stddev .Totals            => population standard deviation
+//	minOf .Totals             => 10
+//	maxOf .Totals             => 35
+//	percentile 95 .Latencies  => 95th percentile, interpolated
+//	sparkline .Totals         => "▁▂▇▃"
+//	histogram 5 .Latencies    => distribution in 5 buckets, as text bars
+func Statistics() FuncMap {
+	return FuncMap{
+		"sum":        Sum,
+		"avg":        Mean,
+		"mean":       Mean,
+		"median":     Median,
+		"stddev":     StdDev,
+		"minOf":      MinOf,
+		"maxOf":      MaxOf,
+		"percentile": Percentile,
+		"sparkline":  Sparkline,
+		"histogram":  Histogram,
+	}
+}
+
+var errEmptyList = errors.New("empty list")
+
+// numbers returns the values of list, which must all be integers or
+// finite floating point numbers.
+func numbers(list interface{}) ([]float64, error) {
+	values, err := listValues(list)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]float64, len(values))
+	for i, v := range values {
+		rv, _ := indirect(reflect.ValueOf(v))
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			rs[i] = float64(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			rs[i] = float64(rv.Uint())
+		case reflect.Float32, reflect.Float64:
+			if f := rv.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+				return nil, fmt.Errorf("not a finite number: %v", v)
+			}
+			rs[i] = rv.Float()
+		default:
+			return nil, fmt.Errorf("not a number: %v", v)
+		}
+	}
+	return rs, nil
+}
+
+// Sum returns the sum of the numbers in list, or 0 if there are none.
+func Sum(list interface{}) (float64, error) {
+	xs, err := numbers(list)
+	if err != nil {
+		return 0, err
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum, nil
+}
+
+// Mean returns the arithmetic mean of the numbers in list.
+func Mean(list interface{}) (float64, error) {
+	xs, err := numbers(list)
+	if err != nil {
+		return 0, err
+	}
+	if len(xs) == 0 {
+		return 0, errEmptyList
+	}
+	return mean(xs), nil
+}
+
+func mean(xs []float64) float64 {
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// Median returns the middle number of list, or the mean of the two middle
+// numbers.
+func Median(list interface{}) (float64, error) {
+	return Percentile(50, list)
+}
+
+// StdDev returns the population standard deviation of the numbers in list.
+func StdDev(list interface{}) (float64, error) {
+	xs, err := numbers(list)
+	if err != nil {
+		return 0, err
+	}
+	if len(xs) == 0 {
+		return 0, errEmptyList
+	}
+	m := mean(xs)
+	var ss float64
+	for _, x := range xs {
+		ss += (x - m) * (x - m)
+	}
+	return math.Sqrt(ss / float64(len(xs))), nil
+}
+
+// MinOf returns the smallest number in list.
+func MinOf(list interface{}) (float64, error) {
+	xs, err := sortedNumbers(list)
+	if err != nil {
+		return 0, err
+	}
+	return xs[0], nil
+}
+
+// MaxOf returns the largest number in list.
+func MaxOf(list interface{}) (float64, error) {
+	xs, err := sortedNumbers(list)
+	if err != nil {
+		return 0, err
+	}
+	return xs[len(xs)-1], nil
+}
+
+// Percentile returns the p-th percentile of the numbers in list, with p
+// from 0 to 100, interpolating linearly between the closest ranks.
+func Percentile(p float64, list interface{}) (float64, error) {
+	if p < 0 || p > 100 || math.IsNaN(p) {
+		return 0, fmt.Errorf("percentile %v out of range [0, 100]", p)
+	}
+	xs, err := sortedNumbers(list)
+	if err != nil {
+		return 0, err
+	}
+	rank := p / 100 * float64(len(xs)-1)
+	lo := int(math.Floor(rank))
+	if lo == len(xs)-1 {
+		return xs[lo], nil
+	}
+	return xs[lo] + (rank-float64(lo))*(xs[lo+1]-xs[lo]), nil
+}
+
+// sortedNumbers returns the numbers of list in ascending order, or an error
+// if there are none.
+func sortedNumbers(list interface{}) ([]float64, error) {
+	xs, err := numbers(list)
+	if err != nil {
+		return nil, err
+	}
+	if len(xs) == 0 {
+		return nil, errEmptyList
+	}
+	sort.Float64s(xs)
+	return xs, nil
+}
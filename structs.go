@@ -0,0 +1,164 @@
+package funcmaps
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Structs returns functions inspecting structs, for generic templates such
+// as admin tables over values of any type:
+//
+//	fieldNames .User            => [ID Name Email]
+//	hasField "Email" .User      => true
+//	tagValue "Email" "json" .   => "email,omitempty"
+//	toMap .User                 => map[id:1 name:Ada]
+func Structs() FuncMap {
+	return FuncMap{
+		"toMap":      ToMap,
+		"fieldNames": FieldNames,
+		"hasField":   HasField,
+		"tagValue":   TagValue,
+	}
+}
+
+// structValue returns v, or the struct v points to.
+func structValue(v interface{}) (reflect.Value, error) {
+	rv, isNil := indirect(reflect.ValueOf(v))
+	if isNil || rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("expected struct, got %T", v)
+	}
+	return rv, nil
+}
+
+// exportedFields returns the exported fields of typ, including those
+// promoted from embedded structs, but not the embedded structs themselves.
+func exportedFields(typ reflect.Type) []reflect.StructField {
+	var rs []reflect.StructField
+	for _, f := range reflect.VisibleFields(typ) {
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.IsExported() && !(f.Anonymous && ft.Kind() == reflect.Struct) {
+			rs = append(rs, f)
+		}
+	}
+	return rs
+}
+
+// ToMap returns the exported fields of the struct v as a map keyed as
+// encoding/json would: by the name of their json tag if any, leaving out
+// fields tagged "-" and empty fields tagged omitempty. Field values are not
+// converted.
+func ToMap(v interface{}) (map[string]interface{}, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+	rs := map[string]interface{}{}
+	for _, f := range exportedFields(rv.Type()) {
+		name, opts := f.Name, ""
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			var tagName string
+			tagName, opts, _ = cutString(tag, ",")
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		fv, ok := fieldByIndex(rv, f.Index)
+		if !ok {
+			continue // promoted through a nil embedded pointer
+		}
+		if hasOption(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+		rs[name] = fv.Interface()
+	}
+	return rs, nil
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex, returning false instead of
+// panicking for fields of nil embedded pointers.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// hasOption reports whether the comma separated options of a tag include
+// opt.
+func hasOption(opts, opt string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyValue reports whether v is empty as for the omitempty option of
+// encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return v.IsZero()
+	}
+	return false
+}
+
+// FieldNames returns the names of the exported fields of the struct v, in
+// declaration order, including those promoted from embedded structs.
+func FieldNames(v interface{}) ([]string, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := exportedFields(rv.Type())
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	return names, nil
+}
+
+// HasField reports whether v is a struct, or a pointer to one, with an
+// exported field called name.
+func HasField(name string, v interface{}) bool {
+	rv, err := structValue(v)
+	if err != nil {
+		return false
+	}
+	f, ok := rv.Type().FieldByName(name)
+	return ok && f.IsExported()
+}
+
+// TagValue returns the value of the tag key of the exported field of the
+// struct v called field, or "" if the field has no such tag.
+func TagValue(field, key string, v interface{}) (string, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return "", err
+	}
+	f, ok := rv.Type().FieldByName(field)
+	if !ok || !f.IsExported() {
+		return "", fmt.Errorf("%s has no field %s", rv.Type(), field)
+	}
+	return f.Tag.Get(key), nil
+}
@@ -0,0 +1,69 @@
+package funcmaps
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testBase struct {
+	ID int `json:"id"`
+}
+
+type testUser struct {
+	testBase
+	*testAudit
+	Name    string `json:"name" db:"user_name"`
+	Email   string `json:"email,omitempty"`
+	Secret  string `json:"-"`
+	private int
+}
+
+type testAudit struct {
+	Editor string
+}
+
+func TestToMap(t *testing.T) {
+	got, err := ToMap(&testUser{testBase: testBase{ID: 1}, Name: "Ada", Secret: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"id": 1, "name": "Ada"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, err := ToMap(42); err == nil {
+		t.Error("ToMap(42): got nil error")
+	}
+	if _, err := ToMap((*testUser)(nil)); err == nil {
+		t.Error("ToMap of a nil pointer: got nil error")
+	}
+}
+
+func TestFieldNames(t *testing.T) {
+	got, err := FieldNames(testUser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ID", "Editor", "Name", "Email", "Secret"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, err := FieldNames("x"); err == nil {
+		t.Error(`FieldNames("x"): got nil error`)
+	}
+}
+
+func TestHasFieldTagValue(t *testing.T) {
+	u := testUser{}
+	if !HasField("Name", u) || HasField("private", u) || HasField("Name", 1) {
+		t.Error("HasField: wrong results")
+	}
+	if v, err := TagValue("Name", "db", &u); err != nil || v != "user_name" {
+		t.Errorf(`TagValue("Name", "db") = %q, %v`, v, err)
+	}
+	if v, err := TagValue("Name", "xml", u); err != nil || v != "" {
+		t.Errorf(`TagValue("Name", "xml") = %q, %v`, v, err)
+	}
+	if _, err := TagValue("Missing", "json", u); err == nil {
+		t.Error("TagValue of a missing field: got nil error")
+	}
+}
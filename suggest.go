@@ -0,0 +1,82 @@
+package funcmaps
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxSuggestions is the number of names returned by SuggestFunc.
+const maxSuggestions = 3
+
+// SuggestFunc returns the names of the functions of fms closest to name,
+// closest first, for "did you mean" messages about a misspelled function.
+// Without fms, the functions of All are searched. Names differing only in
+// case are closest; otherwise names are close when their Levenshtein
+// distance is at most a third of the length of name, rounded, so that
+// short names only get suggestions differing by a letter.
+func SuggestFunc(name string, fms ...FuncMap) []string {
+	if len(fms) == 0 {
+		fms = []FuncMap{All()}
+	}
+	type candidate struct {
+		name string
+		dist int
+	}
+	var cs []candidate
+	max := (len([]rune(name)) + 1) / 3
+	seen := map[string]bool{}
+	for _, fm := range fms {
+		for fn := range fm {
+			if fn == name || seen[fn] {
+				continue
+			}
+			seen[fn] = true
+			if d := Levenshtein(strings.ToLower(name), strings.ToLower(fn)); d <= max {
+				cs = append(cs, candidate{fn, d})
+			}
+		}
+	}
+	sort.Slice(cs, func(i, j int) bool {
+		if cs[i].dist != cs[j].dist {
+			return cs[i].dist < cs[j].dist
+		}
+		return cs[i].name < cs[j].name
+	})
+	if len(cs) > maxSuggestions {
+		cs = cs[:maxSuggestions]
+	}
+	names := make([]string, len(cs))
+	for i, c := range cs {
+		names[i] = c.name
+	}
+	return names
+}
+
+var undefinedFuncRe = regexp.MustCompile(`function "([^"]+)" not defined`)
+
+// SuggestFuncError returns err with suggestions from SuggestFunc added if it
+// is a parse error about an undefined function, or else err:
+//
+//	template: page.html:3: function "toUpperr" not defined (did you mean "toUpper"?)
+//
+// The returned error wraps err.
+func SuggestFuncError(err error, fms ...FuncMap) error {
+	if err == nil {
+		return nil
+	}
+	m := undefinedFuncRe.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	names := SuggestFunc(m[1], fms...)
+	if len(names) == 0 {
+		return err
+	}
+	for i, name := range names {
+		names[i] = strconv.Quote(name)
+	}
+	return fmt.Errorf("%w (did you mean %s?)", err, strings.Join(names, " or "))
+}
@@ -0,0 +1,149 @@
+package funcmaps
+
+import (
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// Tables returns functions rendering tables as Markdown and plain text,
+// with aligned columns.
+//
+// Headers are a list of names. Rows are lists of cells, or maps and
+// structs whose values are looked up by header name:
+//
+//	{{ mdTable .Columns .People }}
+func Tables() FuncMap {
+	return FuncMap{
+		"mdTable":    MarkdownTable,
+		"asciiTable": ASCIITable,
+	}
+}
+
+var mdCellReplacer = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+var asciiCellReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ")
+
+// MarkdownTable returns a GitHub flavored Markdown table, with pipes in
+// cells escaped and line breaks written as <br>.
+func MarkdownTable(headers, rows interface{}) (string, error) {
+	cells, widths, err := tableCells(headers, rows, mdCellReplacer.Replace)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for i, row := range cells {
+		writeTableRow(&b, row, widths, "| ", " | ", " |")
+		if i == 0 {
+			dashes := make([]string, len(widths))
+			for j, w := range widths {
+				dashes[j] = strings.Repeat("-", w)
+			}
+			writeTableRow(&b, dashes, widths, "| ", " | ", " |")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// ASCIITable returns a plain text table drawn with ASCII characters, for
+// terminals and emails.
+func ASCIITable(headers, rows interface{}) (string, error) {
+	cells, widths, err := tableCells(headers, rows, asciiCellReplacer.Replace)
+	if err != nil {
+		return "", err
+	}
+	var rule strings.Builder
+	rule.WriteByte('+')
+	for _, w := range widths {
+		rule.WriteString(strings.Repeat("-", w+2))
+		rule.WriteByte('+')
+	}
+	rule.WriteByte('\n')
+
+	var b strings.Builder
+	b.WriteString(rule.String())
+	for i, row := range cells {
+		writeTableRow(&b, row, widths, "| ", " | ", " |")
+		if i == 0 || i == len(cells)-1 {
+			b.WriteString(rule.String())
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// isList reports whether v is a slice or an array.
+func isList(v interface{}) bool {
+	rv, _ := indirect(reflect.ValueOf(v))
+	return rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array
+}
+
+func writeTableRow(b *strings.Builder, row []string, widths []int, left, sep, right string) {
+	b.WriteString(left)
+	for i, cell := range row {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(cell)
+		b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+	}
+	b.WriteString(right)
+	b.WriteByte('\n')
+}
+
+// tableCells returns the header and rows as strings escaped by escape, the
+// header first, and the width of each column.
+func tableCells(headers, rows interface{}, escape func(string) string) ([][]string, []int, error) {
+	hs, err := listValues(headers)
+	if err != nil {
+		return nil, nil, err
+	}
+	rs, err := listValues(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make([]string, len(hs))
+	for i, h := range hs {
+		names[i] = stringifyValue(h)
+	}
+	cells := make([][]string, 0, len(rs)+1)
+	cells = append(cells, names)
+	for _, r := range rs {
+		var values []interface{}
+		if isList(r) {
+			if values, err = listValues(r); err != nil {
+				return nil, nil, err
+			}
+		} else {
+			values = make([]interface{}, len(names))
+			for i, name := range names {
+				values[i], _ = keyValue(r, name)
+			}
+		}
+		row := make([]string, len(names))
+		for i := range row {
+			if i < len(values) && values[i] != nil {
+				row[i] = stringifyValue(values[i])
+			}
+		}
+		cells = append(cells, row)
+	}
+	widths := make([]int, len(names))
+	for i, row := range cells {
+		for j, cell := range row {
+			cell = escape(cell)
+			if i == 0 && cell == "" {
+				cell = " "
+			}
+			row[j] = cell
+			if n := utf8.RuneCountInString(cell); n > widths[j] {
+				widths[j] = n
+			}
+		}
+	}
+	for j := range widths {
+		if widths[j] < 3 {
+			widths[j] = 3 // Markdown needs at least three dashes
+		}
+	}
+	return cells, widths, nil
+}
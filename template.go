@@ -0,0 +1,320 @@
+package funcmaps
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"sync"
+	ttemplate "text/template"
+	"time"
+)
+
+// Templates is a set of html/template templates parsed with a FuncMap.
+// It is safe for concurrent use.
+type Templates struct {
+	funcs     FuncMap
+	textFuncs FuncMap       // functions for text templates
+	dev       time.Duration // poll interval in development mode, or zero
+	layout    string        // default layout for Render
+	cache     *Cache        // cache of cachedPartial, or nil
+	hooks     []Hook        // applied to HTML output
+	state     []StateFuncs  // per-execution functions
+
+	mu      sync.RWMutex
+	set     *templateSet
+	sources []templateSource
+	mtimes  map[string]time.Time // modification times of parsed files, in development mode
+	checked time.Time            // last development mode check
+}
+
+// templateSource is a call to a Parse method, replayed when reloading.
+type templateSource struct {
+	fsys  fs.FS
+	files func() ([]string, error) // names of the files parsed
+	parse func(*templateSet) error
+}
+
+// Option configures Templates.
+type Option func(*Templates)
+
+// WithDevMode makes Templates check the parsed files for changes at most
+// every poll interval, re-parsing them all when any file was added, removed
+// or modified, so template edits show up without restarting the program.
+// Parse errors are returned from the next execution.
+func WithDevMode(poll time.Duration) Option {
+	return func(t *Templates) {
+		if poll <= 0 {
+			poll = time.Second
+		}
+		t.dev = poll
+	}
+}
+
+// New returns an empty template set using the functions in fm.
+func New(fm FuncMap, opts ...Option) *Templates {
+	t := &Templates{funcs: fm}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.textFuncs == nil {
+		t.textFuncs = TextDefault()
+	}
+	t.set = t.newSet()
+	return t
+}
+
+func (t *Templates) newSet() *templateSet {
+	master := template.New("").Funcs(template.FuncMap(t.funcs))
+	master.Funcs(renderFuncs(nil, nil)).Funcs(stateFuncs(t.state, NewExecState()))
+	text := ttemplate.New("").Funcs(ttemplate.FuncMap(t.textFuncs))
+	text.Funcs(textRenderFuncs(nil, nil)).Funcs(ttemplate.FuncMap(stateFuncs(t.state, NewExecState())))
+	return &templateSet{
+		master: master,
+		funcs:  t.funcs,
+		cache:  t.cache,
+		state:  t.state,
+		text:   text,
+	}
+}
+
+// templateSet is a parsed template set and a pool of clones of it.
+//
+// The master set is never executed, so that it can always be cloned, and
+// each clone is used by a single execution at a time, so that functions
+// bound to that execution can be set on it with Funcs.
+type templateSet struct {
+	master *template.Template
+	funcs  FuncMap // functions of the master set, wrapped in each clone
+	cache  *Cache
+	state  []StateFuncs
+	pool   sync.Pool
+	text   *ttemplate.Template // text templates, never executed either
+	tpool  sync.Pool           // clones of text
+
+	lookupOnce sync.Once
+	lookup     *template.Template // clone of master for Lookup, or nil
+}
+
+// setClone is a clone of the master set and the recorder of its failed calls.
+type setClone struct {
+	t     *template.Template
+	calls *callRecorder
+}
+
+// with calls fn with a clone of the set, with the functions in bound
+// replacing the per-execution functions. Errors are returned as
+// *RenderError, with name as the template name when the error has none.
+// Function calls fail once ctx is done, if ctx is not nil.
+func (s *templateSet) with(ctx context.Context, name string, bound template.FuncMap, fn func(*template.Template) error) error {
+	c, ok := s.pool.Get().(*setClone)
+	if !ok {
+		t, err := s.master.Clone()
+		if err != nil {
+			return err
+		}
+		c = &setClone{t: t, calls: &callRecorder{}}
+		c.t.Funcs(template.FuncMap(c.calls.wrap(s.funcs)))
+	}
+	defer s.pool.Put(c)
+	c.calls.reset(ctx)
+	fm := renderFuncs(c.t, s.cache)
+	if len(s.state) > 0 {
+		for k, f := range stateFuncs(s.state, NewExecState()) {
+			fm[k] = f
+		}
+	}
+	for k, f := range bound {
+		fm[k] = f
+	}
+	c.t.Funcs(fm)
+	return newRenderError(name, fn(c.t), c.calls.failed)
+}
+
+// withText is like with for the text templates of the set.
+func (s *templateSet) withText(name string, fn func(*ttemplate.Template) error) error {
+	c, ok := s.tpool.Get().(*ttemplate.Template)
+	if !ok {
+		var err error
+		if c, err = s.text.Clone(); err != nil {
+			return err
+		}
+	}
+	defer s.tpool.Put(c)
+	fm := textRenderFuncs(c, s.cache)
+	for k, f := range stateFuncs(s.state, NewExecState()) {
+		fm[k] = f
+	}
+	c.Funcs(fm)
+	return newRenderError(name, fn(c), nil)
+}
+
+// TemplateFuncs returns the functions Templates adds to every template,
+// such as partial and layout, and those of fns, for checking templates
+// with Lint or ParseUsage. The functions of Templates fail when called
+// outside of it.
+func TemplateFuncs(fns ...StateFuncs) FuncMap {
+	return Combined(FuncMap(renderFuncs(nil, nil)), FuncMap(stateFuncs(fns, NewExecState())))
+}
+
+// ParseFS parses the templates in fsys matching patterns, as
+// template.ParseFS does, adding them to the set.
+func (t *Templates) ParseFS(fsys fs.FS, patterns ...string) error {
+	return t.addSource(templateSource{
+		fsys:  fsys,
+		files: func() ([]string, error) { return globFiles(fsys, patterns) },
+		parse: func(set *templateSet) error {
+			_, err := set.master.ParseFS(fsys, patterns...)
+			return err
+		},
+	})
+}
+
+// globFiles returns the names of the files in fsys matching patterns.
+func globFiles(fsys fs.FS, patterns []string) ([]string, error) {
+	var rs []string
+	for _, pattern := range patterns {
+		names, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, names...)
+	}
+	return rs, nil
+}
+
+// addSource parses src into the set and records it for reloading.
+func (t *Templates) addSource(src templateSource) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := src.parse(t.set); err != nil {
+		return t.parseError(err)
+	}
+	// drop clones made before the new templates were added
+	t.set = &templateSet{master: t.set.master, funcs: t.set.funcs, cache: t.set.cache, state: t.set.state, text: t.set.text}
+	t.sources = append(t.sources, src)
+	if t.dev > 0 {
+		mtimes, err := t.stat()
+		if err != nil {
+			return err
+		}
+		t.mtimes, t.checked = mtimes, time.Now()
+	}
+	return nil
+}
+
+// parseError returns err with "did you mean" suggestions from the
+// functions of the set added if it is about an undefined function.
+func (t *Templates) parseError(err error) error {
+	return SuggestFuncError(err, t.funcs, t.textFuncs, FuncMap(renderFuncs(nil, nil)), FuncMap(stateFuncs(t.state, NewExecState())))
+}
+
+// ExecuteTemplate applies the template with the given name to data,
+// writing the output to w. Execution errors are returned as *RenderError.
+func (t *Templates) ExecuteTemplate(w io.Writer, name string, data interface{}) error {
+	return t.execute(w, name, data, nil)
+}
+
+func (t *Templates) execute(w io.Writer, name string, data interface{}, hooks []Hook) error {
+	set, err := t.current()
+	if err != nil {
+		return err
+	}
+	out, flush := t.output(w, hooks)
+	err = set.with(nil, name, nil, func(c *template.Template) error {
+		return c.ExecuteTemplate(out, name, data)
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// Lookup returns the template with the given name, or nil. It belongs to a
+// copy of the set, so that executing it does not keep the set from being
+// cloned for later executions. The copy is made by the first Lookup after
+// templates are parsed or reloaded, and shared by the later ones.
+func (t *Templates) Lookup(name string) *template.Template {
+	set, err := t.current()
+	if err != nil || set.master.Lookup(name) == nil {
+		return nil
+	}
+	set.lookupOnce.Do(func() {
+		set.lookup, _ = set.master.Clone()
+	})
+	if set.lookup == nil {
+		return nil
+	}
+	return set.lookup.Lookup(name)
+}
+
+// Defined reports whether the set has a template with the given name.
+func (t *Templates) Defined(name string) bool {
+	set, err := t.current()
+	return err == nil && set.master.Lookup(name) != nil
+}
+
+// current returns the template set to execute, reloading it first when in
+// development mode and the files changed.
+func (t *Templates) current() (*templateSet, error) {
+	t.mu.RLock()
+	set, stale := t.set, t.dev > 0 && time.Since(t.checked) >= t.dev
+	t.mu.RUnlock()
+	if !stale {
+		return set, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if time.Since(t.checked) < t.dev {
+		return t.set, nil // reloaded by another goroutine
+	}
+	t.checked = time.Now()
+	mtimes, err := t.stat()
+	if err != nil {
+		return nil, err
+	}
+	if sameModTimes(mtimes, t.mtimes) {
+		return t.set, nil
+	}
+	set = t.newSet()
+	for _, src := range t.sources {
+		if err := src.parse(set); err != nil {
+			return nil, t.parseError(err)
+		}
+	}
+	t.set, t.mtimes = set, mtimes
+	return set, nil
+}
+
+// stat returns the modification time of every file matched by the sources.
+func (t *Templates) stat() (map[string]time.Time, error) {
+	mtimes := map[string]time.Time{}
+	for i, src := range t.sources {
+		names, err := src.files()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			fi, err := fs.Stat(src.fsys, name)
+			if err != nil {
+				return nil, err
+			}
+			mtimes[fmt.Sprintf("%d:%s", i, name)] = fi.ModTime()
+		}
+	}
+	return mtimes, nil
+}
+
+func sameModTimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !w.Equal(v) {
+			return false
+		}
+	}
+	return true
+}
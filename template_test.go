@@ -0,0 +1,83 @@
+package funcmaps
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTextTemplateStateFuncs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"list.txt": {Data: []byte(`{{range .}}{{counter "n"}}:{{cycle "a" "b"}}:{{uniqueID "x"}} {{end}}{{partial "tail" .}}{{define "tail"}}{{len .}}{{end}}`)},
+	}
+	ts := New(Default(), WithState(Counters, ElementIDs))
+	if err := ts.ParseTextFS(fsys, "*.txt"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		var buf bytes.Buffer
+		if err := ts.ExecuteText(&buf, "list.txt", []int{1, 2}); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := buf.String(), "1:a:x-1 2:b:x-2 2"; got != want {
+			t.Errorf("execution %d: got %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestTemplatesExecuteFormats(t *testing.T) {
+	fsys := fstest.MapFS{
+		"mail.html": {Data: []byte(`<p>{{.}}</p>`)},
+		"mail.txt":  {Data: []byte(`{{.}}`)},
+	}
+	ts := New(Default())
+	if err := ts.ParseFS(fsys, "*.html"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.ParseTextFS(fsys, "*.txt"); err != nil {
+		t.Fatal(err)
+	}
+	var html, text bytes.Buffer
+	if err := ts.ExecuteFormats(&html, &text, "mail", "<b>"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := html.String(), "<p>&lt;b&gt;</p>"; got != want {
+		t.Errorf("html: got %q, want %q", got, want)
+	}
+	if got, want := text.String(), "<b>"; got != want {
+		t.Errorf("text: got %q, want %q", got, want)
+	}
+	if err := ts.ExecuteFormats(&html, &text, "missing", nil); err == nil {
+		t.Error("ExecuteFormats of a missing template: got nil error")
+	}
+}
+
+func TestTemplatesLookup(t *testing.T) {
+	ts := New(Default())
+	if err := ts.ParseFS(fstest.MapFS{"a.html": {Data: []byte(`{{upper .}}`)}}, "*.html"); err != nil {
+		t.Fatal(err)
+	}
+	a := ts.Lookup("a.html")
+	if a == nil || ts.Lookup("a.html") != a {
+		t.Fatalf("Lookup returned %v, then another template", a)
+	}
+	if ts.Lookup("b.html") != nil {
+		t.Error("Lookup of a missing template: got a template")
+	}
+	var buf bytes.Buffer
+	if err := a.Execute(&buf, "x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.ExecuteTemplate(&buf, "a.html", "y"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "XY"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if err := ts.ParseFS(fstest.MapFS{"b.html": {Data: []byte(`b`)}}, "*.html"); err != nil {
+		t.Fatal(err)
+	}
+	if ts.Lookup("b.html") == nil {
+		t.Error("Lookup of a template parsed after a Lookup: got nil")
+	}
+}
@@ -0,0 +1,119 @@
+package funcmaps
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Term returns functions coloring terminal output with ANSI escape codes,
+// for command line tools using text/template:
+//
+//	{{ color "red" "error:" | bold }} {{ .Message }}
+//
+// Colors are named (black, red, green, yellow, blue, magenta, cyan, white,
+// each also prefixed with "bright", as in "brightRed"), or given in hex
+// for 24-bit color terminals. Use TermAuto to disable them when the output
+// is not a terminal.
+func Term(opts ...TermOption) FuncMap {
+	t := &termOptions{enabled: true}
+	for _, opt := range opts {
+		opt(t)
+	}
+	style := func(code, s string) string {
+		if !t.enabled || s == "" {
+			return s
+		}
+		return "\x1b[" + code + "m" + s + "\x1b[0m"
+	}
+	colorFunc := func(bg bool) func(string, string) (string, error) {
+		return func(name, s string) (string, error) {
+			code, err := ansiColor(name, bg)
+			if err != nil {
+				return "", err
+			}
+			return style(code, s), nil
+		}
+	}
+	return FuncMap{
+		"color":     colorFunc(false),
+		"bgColor":   colorFunc(true),
+		"bold":      func(s string) string { return style("1", s) },
+		"underline": func(s string) string { return style("4", s) },
+		"stripANSI": StripANSI,
+	}
+}
+
+// TermOption configures Term.
+type TermOption func(*termOptions)
+
+type termOptions struct {
+	enabled bool
+}
+
+// TermEnabled enables or disables the escape codes; when disabled, the
+// functions return their text unchanged.
+func TermEnabled(enabled bool) TermOption {
+	return func(t *termOptions) {
+		t.enabled = enabled
+	}
+}
+
+// TermAuto enables the escape codes only when f is a terminal and the
+// NO_COLOR environment variable is not set.
+func TermAuto(f *os.File) TermOption {
+	return func(t *termOptions) {
+		t.enabled = IsTerminal(f) && os.Getenv("NO_COLOR") == ""
+	}
+}
+
+// IsTerminal reports whether f is a terminal, or another character device.
+func IsTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+var ansiColors = map[string]int{
+	"black": 0, "red": 1, "green": 2, "yellow": 3,
+	"blue": 4, "magenta": 5, "cyan": 6, "white": 7,
+}
+
+// ansiColor returns the SGR parameters selecting the color name, as a
+// foreground or background color.
+func ansiColor(name string, bg bool) (string, error) {
+	if strings.HasPrefix(name, "#") {
+		c, err := HexToRGB(name)
+		if err != nil {
+			return "", err
+		}
+		layer := 38
+		if bg {
+			layer = 48
+		}
+		return fmt.Sprintf("%d;2;%d;%d;%d", layer, c.R, c.G, c.B), nil
+	}
+	lower := strings.ToLower(name)
+	base := 30
+	if strings.HasPrefix(lower, "bright") {
+		lower, base = strings.TrimPrefix(lower, "bright"), 90
+	}
+	n, ok := ansiColors[lower]
+	if !ok {
+		return "", fmt.Errorf("unknown color %q", name)
+	}
+	if bg {
+		base += 10
+	}
+	return fmt.Sprint(base + n), nil
+}
+
+var ansiRe = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// StripANSI removes ANSI escape sequences from s.
+func StripANSI(s string) string {
+	return ansiRe.ReplaceAllString(s, "")
+}
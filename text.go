@@ -0,0 +1,127 @@
+package funcmaps
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// columnGap is the number of spaces between the columns of textColumns.
+const columnGap = 2
+
+// Text returns functions laying out plain text, for emails and terminal
+// output. Widths are counted in runes.
+//
+// The textColumns function is named so as not to clash with the columns
+// function of Collections, which returns rows rather than text.
+func Text() FuncMap {
+	return FuncMap{
+		"wrap":        Wrap,
+		"wrapWith":    WrapWith,
+		"center":      Center,
+		"padLeft":     PadLeft,
+		"padRight":    PadRight,
+		"textColumns": TextColumns,
+	}
+}
+
+// Wrap wraps the lines of s at word boundaries so that they are at most
+// width runes long, except for words longer than width. Existing line
+// breaks are kept.
+func Wrap(width int, s string) string {
+	return WrapWith(width, "", s)
+}
+
+// WrapWith is Wrap, prefixing every line with indent, which counts towards
+// the width.
+func WrapWith(width int, indent, s string) string {
+	avail := width - utf8.RuneCountInString(indent)
+	var b strings.Builder
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(indent)
+		n := 0
+		for j, word := range strings.Fields(line) {
+			wn := utf8.RuneCountInString(word)
+			if j > 0 {
+				if n+1+wn > avail {
+					b.WriteByte('\n')
+					b.WriteString(indent)
+					n = 0
+				} else {
+					b.WriteByte(' ')
+					n++
+				}
+			}
+			b.WriteString(word)
+			n += wn
+		}
+	}
+	return b.String()
+}
+
+// Center pads s with spaces on both sides to width runes; when the padding
+// is uneven, the extra space goes on the right.
+func Center(width int, s string) string {
+	pad := width - utf8.RuneCountInString(s)
+	if pad <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", pad/2) + s + strings.Repeat(" ", pad-pad/2)
+}
+
+// PadLeft pads s with spaces on the left to width runes, right aligning it.
+func PadLeft(width int, s string) string {
+	if pad := width - utf8.RuneCountInString(s); pad > 0 {
+		return strings.Repeat(" ", pad) + s
+	}
+	return s
+}
+
+// PadRight pads s with spaces on the right to width runes.
+func PadRight(width int, s string) string {
+	if pad := width - utf8.RuneCountInString(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
+
+// TextColumns lays out items in n columns, filled column by column as by
+// Columns, each as wide as its widest item:
+//
+//	textColumns 2 (split "," "a,b,c") => "a  c\nb"
+func TextColumns(n int, items interface{}) (string, error) {
+	rows, err := Columns(n, items)
+	if err != nil {
+		return "", err
+	}
+	cells := make([][]string, len(rows))
+	var widths []int
+	for i, row := range rows {
+		cells[i] = make([]string, len(row))
+		for j, v := range row {
+			s := stringifyValue(v)
+			cells[i][j] = s
+			if j == len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := utf8.RuneCountInString(s); w > widths[j] {
+				widths[j] = w
+			}
+		}
+	}
+	var b strings.Builder
+	for i, row := range cells {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		for j, s := range row {
+			if j < len(row)-1 {
+				s = PadRight(widths[j]+columnGap, s)
+			}
+			b.WriteString(s)
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,77 @@
+package funcmaps
+
+import (
+	"fmt"
+	"html/template"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Tokens maps the names of design tokens, such as "color-primary" or
+// "space-4", to their CSS values.
+type Tokens map[string]string
+
+var (
+	tokenNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	// tokenValueRe rejects values that could end a declaration, rule or
+	// style element.
+	tokenValueRe = regexp.MustCompile(`[;{}<>\\]|/\*`)
+)
+
+// Themes returns functions referencing the design tokens of tokens, so that
+// their values are defined once, in Go:
+//
+//	<style>{{themeStyles}}</style>         => :root { --color-primary: #0055ff; ... }
+//	<p style="color: {{cssVar "color-primary"}}">  => var(--color-primary)
+//	<meta name="theme-color" content="{{themeValue "color-primary"}}">
+//
+// cssVar and themeValue fail for tokens that are not defined, catching
+// typos at render time. Themes returns an error if a name is not a valid
+// CSS identifier or a value could break out of a declaration. Later changes
+// to tokens do not change the functions.
+func Themes(tokens Tokens) (FuncMap, error) {
+	own := make(Tokens, len(tokens))
+	for name, value := range tokens {
+		own[name] = value
+	}
+	tokens = own
+	names := make([]string, 0, len(tokens))
+	for name, value := range tokens {
+		if !tokenNameRe.MatchString(name) {
+			return nil, fmt.Errorf("themes: invalid token name %q", name)
+		}
+		if tokenValueRe.MatchString(value) {
+			return nil, fmt.Errorf("themes: invalid value %q of token %q", value, name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(":root {")
+	for _, name := range names {
+		fmt.Fprintf(&b, " --%s: %s;", name, tokens[name])
+	}
+	b.WriteString(" }")
+	styles := template.CSS(b.String())
+	lookup := func(name string) (string, error) {
+		v, ok := tokens[name]
+		if !ok {
+			return "", fmt.Errorf("unknown theme token %q", name)
+		}
+		return v, nil
+	}
+	return FuncMap{
+		"cssVar": func(name string) (template.CSS, error) {
+			if _, err := lookup(name); err != nil {
+				return "", err
+			}
+			return template.CSS("var(--" + name + ")"), nil
+		},
+		"themeValue": func(name string) (template.CSS, error) {
+			v, err := lookup(name)
+			return template.CSS(v), err
+		},
+		"themeStyles": func() template.CSS { return styles },
+	}, nil
+}
@@ -0,0 +1,26 @@
+package funcmaps
+
+import (
+	"html/template"
+	"testing"
+)
+
+func TestThemesCopyTokens(t *testing.T) {
+	tokens := Tokens{"color-primary": "#0055ff"}
+	fm, err := Themes(tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens["color-primary"] = "red; } body { display: none"
+	tokens["space-4"] = "1rem"
+	themeValue := fm["themeValue"].(func(string) (template.CSS, error))
+	if v, err := themeValue("color-primary"); err != nil || v != "#0055ff" {
+		t.Errorf("themeValue(color-primary) = %q, %v, want #0055ff", v, err)
+	}
+	if _, err := themeValue("space-4"); err == nil {
+		t.Error("themeValue(space-4): got nil error for a token added later")
+	}
+	if _, err := Themes(Tokens{"a": "x; y"}); err == nil {
+		t.Error("Themes with an invalid value: got nil error")
+	}
+}
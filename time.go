@@ -9,34 +9,61 @@ import "time"
 // Date can be a `time.Time` or an `int, int32, int64`.
 // In the later case, it is treated as seconds since UNIX
 // epoch.
+//
+// The layout is a Go reference layout, the name of one such as "RFC3339",
+// "Kitchen" or "ISO8601" (see TimeLayouts), or a strftime format such as
+// "%Y-%m-%d".
 func FormatTime(fmt string, zone string, date interface{}) string {
-	if zone == "" {
-		zone = "Local"
+	return FormatTimeLocale(fmt, zone, "en", date)
+}
+
+// FormatTimeLocale is FormatTime with the month and day names, and AM and
+// PM, of the language lang, as found in TimeLocales; English is used for
+// other languages.
+func FormatTimeLocale(fmt, zone, lang string, date interface{}) string {
+	return formateDate(fmt, date, zone, time.Local, timeLocale(lang))
+}
+
+// formateDate formats date in zone, or in def if zone is empty.
+func formateDate(fmt string, date interface{}, zone string, def *time.Location, tl *TimeLocale) string {
+	t := asTime(date)
+	loc := def
+	if zone != "" {
+		var err error
+		if loc, err = time.LoadLocation(zone); err != nil {
+			loc = time.UTC
+		}
+	}
+
+	return formatTime(t.In(loc), fmt, tl)
+}
+
+// asTime returns date as a time, as toTime does. Other values give the
+// current time.
+func asTime(date interface{}) time.Time {
+	if t, ok := toTime(date); ok {
+		return t
 	}
-	return formateDate(fmt, date, zone)
+	return time.Now()
 }
 
-func formateDate(fmt string, date interface{}, zone string) string {
-	var t time.Time
+// toTime returns date as a time: a time.Time, non-nil *time.Time, or
+// seconds since the UNIX epoch as an int, int32 or int64. It reports
+// whether date is one of these.
+func toTime(date interface{}) (time.Time, bool) {
 	switch date := date.(type) {
-	default:
-		t = time.Now()
 	case time.Time:
-		t = date
+		return date, true
 	case *time.Time:
-		t = *date
+		if date != nil {
+			return *date, true
+		}
 	case int64:
-		t = time.Unix(date, 0)
+		return time.Unix(date, 0), true
 	case int:
-		t = time.Unix(int64(date), 0)
+		return time.Unix(int64(date), 0), true
 	case int32:
-		t = time.Unix(int64(date), 0)
+		return time.Unix(int64(date), 0), true
 	}
-
-	loc, err := time.LoadLocation(zone)
-	if err != nil {
-		loc, _ = time.LoadLocation("UTC")
-	}
-
-	return t.In(loc).Format(fmt)
+	return time.Time{}, false
 }
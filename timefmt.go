@@ -0,0 +1,282 @@
+package funcmaps
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeLayouts are the named layouts accepted by FormatTime, matched
+// without regard to case. Applications may add their own.
+var TimeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"ISO8601":     "2006-01-02T15:04:05Z07:00",
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+	"DateTime":    "2006-01-02 15:04:05",
+	"DateOnly":    "2006-01-02",
+	"TimeOnly":    "15:04:05",
+}
+
+// TimeLocale holds the names used to format times in a language.
+type TimeLocale struct {
+	Months      [12]string
+	ShortMonths [12]string
+	Days        [7]string // from Sunday
+	ShortDays   [7]string
+	AM, PM      string
+}
+
+// TimeLocales maps languages, as ISO 639-1 codes, to their names for
+// FormatTimeLocale. Applications may add their own.
+var TimeLocales = map[string]*TimeLocale{
+	"en": {
+		Months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		ShortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		Days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		ShortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		AM:          "AM", PM: "PM",
+	},
+	"de": {
+		Months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		ShortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		Days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		ShortDays:   [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		AM:          "AM", PM: "PM",
+	},
+	"es": {
+		Months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		ShortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		Days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		ShortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		AM:          "a. m.", PM: "p. m.",
+	},
+	"fr": {
+		Months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		ShortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		ShortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		AM:          "AM", PM: "PM",
+	},
+	"it": {
+		Months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		ShortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		Days:        [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		ShortDays:   [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		AM:          "AM", PM: "PM",
+	},
+	"nl": {
+		Months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		ShortMonths: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		Days:        [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		ShortDays:   [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+		AM:          "a.m.", PM: "p.m.",
+	},
+	"pt": {
+		Months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		ShortMonths: [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		Days:        [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		ShortDays:   [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+		AM:          "AM", PM: "PM",
+	},
+}
+
+// timeLocale returns the TimeLocale of the language of the tag lang, such
+// as "fr" or "fr-CA", or English.
+func timeLocale(lang string) *TimeLocale {
+	base := strings.ToLower(strings.SplitN(strings.Replace(lang, "_", "-", -1), "-", 2)[0])
+	if tl, ok := TimeLocales[base]; ok {
+		return tl
+	}
+	return TimeLocales["en"]
+}
+
+// formatTime formats t with a named, strftime or Go layout, using the
+// names of tl.
+func formatTime(t time.Time, layout string, tl *TimeLocale) string {
+	if strings.Contains(layout, "%") {
+		return strftime(t, layout, tl)
+	}
+	for name, l := range TimeLayouts {
+		if strings.EqualFold(name, layout) {
+			layout = l
+			break
+		}
+	}
+	return formatGo(t, layout, tl)
+}
+
+// goNames are the elements of Go layouts replaced with localized names,
+// longest first.
+var goNames = []string{"January", "Monday", "Jan", "Mon", "PM", "pm"}
+
+// formatGo formats t with the Go layout, replacing the names of months and
+// days, and AM and PM, by those of tl.
+func formatGo(t time.Time, layout string, tl *TimeLocale) string {
+	var b strings.Builder
+	for layout != "" {
+		i, elem := nextGoName(layout)
+		b.WriteString(t.Format(layout[:i]))
+		if elem == "" {
+			break
+		}
+		b.WriteString(localName(t, elem, tl))
+		layout = layout[i+len(elem):]
+	}
+	return b.String()
+}
+
+// nextGoName returns the index and the first of goNames found in layout,
+// or the length of layout. As in the time package, "Jan" and "Mon"
+// followed by a lowercase letter, as in "Monthly", are text.
+func nextGoName(layout string) (int, string) {
+	for i := range layout {
+		for _, name := range goNames {
+			if !strings.HasPrefix(layout[i:], name) {
+				continue
+			}
+			if (name == "Jan" || name == "Mon") && startsWithLowerCase(layout[i+len(name):]) {
+				continue
+			}
+			return i, name
+		}
+	}
+	return len(layout), ""
+}
+
+func startsWithLowerCase(s string) bool {
+	return s != "" && 'a' <= s[0] && s[0] <= 'z'
+}
+
+func localName(t time.Time, elem string, tl *TimeLocale) string {
+	switch elem {
+	case "January":
+		return tl.Months[t.Month()-1]
+	case "Jan":
+		return tl.ShortMonths[t.Month()-1]
+	case "Monday":
+		return tl.Days[t.Weekday()]
+	case "Mon":
+		return tl.ShortDays[t.Weekday()]
+	}
+	ampm := tl.AM
+	if t.Hour() >= 12 {
+		ampm = tl.PM
+	}
+	if elem == "pm" {
+		return strings.ToLower(ampm)
+	}
+	return ampm
+}
+
+// strftime formats t with the C strftime format, using the names of tl.
+// Unknown conversions are written as is.
+func strftime(t time.Time, format string, tl *TimeLocale) string {
+	var b strings.Builder
+	hour12 := t.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			b.WriteByte(format[i])
+			continue
+		}
+		i++
+		switch c := format[i]; c {
+		case 'a':
+			b.WriteString(tl.ShortDays[t.Weekday()])
+		case 'A':
+			b.WriteString(tl.Days[t.Weekday()])
+		case 'b', 'h':
+			b.WriteString(tl.ShortMonths[t.Month()-1])
+		case 'B':
+			b.WriteString(tl.Months[t.Month()-1])
+		case 'c':
+			b.WriteString(strftime(t, "%a %b %e %H:%M:%S %Y", tl))
+		case 'C':
+			fmt.Fprintf(&b, "%02d", t.Year()/100)
+		case 'd':
+			fmt.Fprintf(&b, "%02d", t.Day())
+		case 'D':
+			b.WriteString(t.Format("01/02/06"))
+		case 'e':
+			fmt.Fprintf(&b, "%2d", t.Day())
+		case 'f':
+			fmt.Fprintf(&b, "%06d", t.Nanosecond()/1000)
+		case 'F':
+			b.WriteString(t.Format("2006-01-02"))
+		case 'G':
+			y, _ := t.ISOWeek()
+			b.WriteString(strconv.Itoa(y))
+		case 'H':
+			fmt.Fprintf(&b, "%02d", t.Hour())
+		case 'I':
+			fmt.Fprintf(&b, "%02d", hour12)
+		case 'j':
+			fmt.Fprintf(&b, "%03d", t.YearDay())
+		case 'k':
+			fmt.Fprintf(&b, "%2d", t.Hour())
+		case 'l':
+			fmt.Fprintf(&b, "%2d", hour12)
+		case 'm':
+			fmt.Fprintf(&b, "%02d", int(t.Month()))
+		case 'M':
+			fmt.Fprintf(&b, "%02d", t.Minute())
+		case 'n':
+			b.WriteByte('\n')
+		case 'p':
+			b.WriteString(localName(t, "PM", tl))
+		case 'P':
+			b.WriteString(localName(t, "pm", tl))
+		case 'R':
+			b.WriteString(t.Format("15:04"))
+		case 's':
+			b.WriteString(strconv.FormatInt(t.Unix(), 10))
+		case 'S':
+			fmt.Fprintf(&b, "%02d", t.Second())
+		case 't':
+			b.WriteByte('\t')
+		case 'T':
+			b.WriteString(t.Format("15:04:05"))
+		case 'u':
+			wd := int(t.Weekday())
+			if wd == 0 {
+				wd = 7
+			}
+			b.WriteString(strconv.Itoa(wd))
+		case 'V':
+			_, w := t.ISOWeek()
+			fmt.Fprintf(&b, "%02d", w)
+		case 'w':
+			b.WriteString(strconv.Itoa(int(t.Weekday())))
+		case 'y':
+			fmt.Fprintf(&b, "%02d", t.Year()%100)
+		case 'Y':
+			b.WriteString(strconv.Itoa(t.Year()))
+		case 'z':
+			b.WriteString(t.Format("-0700"))
+		case 'Z':
+			b.WriteString(t.Format("MST"))
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package funcmaps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// TimeoutError is returned by ExecuteWithTimeout when the context of an
+// execution is done before the execution ends.
+type TimeoutError struct {
+	Template string
+	Elapsed  time.Duration // time from the start of the execution
+	Err      error         // the error of the context
+}
+
+// Error returns the template name, elapsed time and context error.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("template %q: aborted after %v: %v", e.Template, e.Elapsed.Round(time.Millisecond), e.Err)
+}
+
+// Unwrap returns the error of the context, such as
+// context.DeadlineExceeded.
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// ExecuteWithTimeout is like ExecuteTemplate, aborting the execution when
+// ctx is done, such as when its deadline passes, and returning a
+// *TimeoutError. Output is buffered and only written to w on success.
+//
+// The execution stops at its next function call or write, so a loop doing
+// neither runs until it ends, in the background.
+func (t *Templates) ExecuteWithTimeout(ctx context.Context, w io.Writer, name string, data interface{}) error {
+	set, err := t.current()
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	out := &contextWriter{ctx: ctx}
+	err = executeContext(ctx, func() error {
+		return set.with(ctx, name, nil, func(c *template.Template) error {
+			return c.ExecuteTemplate(out, name, data)
+		})
+	})
+	if ctx.Err() != nil {
+		return &TimeoutError{Template: name, Elapsed: time.Since(start), Err: ctx.Err()}
+	}
+	if err != nil {
+		return err
+	}
+	b, err := ApplyHooks(out.buf.Bytes(), t.hooks...)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// executeContext calls execute in a goroutine, returning its error, or
+// the error of ctx if it is done first, as for ExecuteWithTimeout and
+// Sandboxed. execute should write to a contextWriter using ctx.
+func executeContext(ctx context.Context, execute func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- execute()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// contextWriter buffers writes, failing them once ctx is done, or with
+// errFull once the output would exceed max bytes, if max is positive.
+type contextWriter struct {
+	ctx     context.Context
+	max     int
+	errFull error
+	buf     bytes.Buffer
+}
+
+func (w *contextWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if w.max > 0 && w.buf.Len()+len(p) > w.max {
+		return 0, w.errFull
+	}
+	return w.buf.Write(p)
+}
@@ -0,0 +1,36 @@
+package funcmaps
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestExecuteWithTimeout(t *testing.T) {
+	ts := New(Default())
+	fsys := fstest.MapFS{
+		"fast.html": {Data: []byte(`{{upper .}}`)},
+		"slow.html": {Data: []byte(`{{range seq 1 10000}}{{range seq 1 10000}}{{upper "x"}}{{end}}{{end}}`)},
+	}
+	if err := ts.ParseFS(fsys, "*.html"); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := ts.ExecuteWithTimeout(context.Background(), &buf, "fast.html", "a"); err != nil || buf.String() != "A" {
+		t.Errorf("fast.html: got %q, %v", buf.String(), err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	buf.Reset()
+	err := ts.ExecuteWithTimeout(ctx, &buf, "slow.html", nil)
+	var te *TimeoutError
+	if !errors.As(err, &te) || !errors.Is(err, context.DeadlineExceeded) || te.Template != "slow.html" {
+		t.Errorf("slow.html: got error %v, want a *TimeoutError", err)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("slow.html: wrote %d bytes on timeout", buf.Len())
+	}
+}
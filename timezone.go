@@ -0,0 +1,156 @@
+package funcmaps
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithDefaultLocation sets the location of now, and of date and
+// inTimezone when given no zone, instead of the local time zone, for
+// dashboards rendering times for one region.
+func WithDefaultLocation(loc *time.Location) MapOption {
+	return func(o *mapOptions) {
+		if loc != nil {
+			o.location = loc
+		}
+	}
+}
+
+// Timezones returns functions converting times between time zones of the
+// IANA time zone database:
+//
+//	inTimezone "Asia/Tokyo" .T  => .T in Tokyo time
+//	tzOffset "Asia/Tokyo" .T    => "+09:00"
+//	listTimezones               => ["Africa/Abidjan" ... "UTC"]
+//
+// An empty zone name is the location set with WithDefaultLocation.
+func Timezones(opts ...MapOption) FuncMap {
+	o := newMapOptions(opts)
+	return o.edit(recordRisks(FuncMap{
+		"inTimezone": func(name string, t interface{}) (time.Time, error) {
+			return inTimezone(name, o.location, t)
+		},
+		"tzOffset": func(name string, t interface{}) (string, error) {
+			t2, err := inTimezone(name, o.location, t)
+			return t2.Format("-07:00"), err
+		},
+		"listTimezones": ListTimezones,
+	}))
+}
+
+// InTimezone returns t, as accepted by FormatTime, in the named location.
+func InTimezone(name string, t interface{}) (time.Time, error) {
+	return inTimezone(name, time.Local, t)
+}
+
+// TZOffset returns the UTC offset of the named location at t, as accepted
+// by FormatTime, such as "+05:30".
+func TZOffset(name string, t interface{}) (string, error) {
+	t2, err := InTimezone(name, t)
+	return t2.Format("-07:00"), err
+}
+
+func inTimezone(name string, def *time.Location, t interface{}) (time.Time, error) {
+	loc := def
+	if name != "" {
+		var err error
+		if loc, err = time.LoadLocation(name); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return asTime(t).In(loc), nil
+}
+
+var timezones struct {
+	once  sync.Once
+	names []string
+}
+
+// zoneDirs are the directories searched for the time zone database, as by
+// the time package.
+var zoneDirs = []string{"/usr/share/zoneinfo", "/usr/share/lib/zoneinfo", "/usr/lib/locale/TZ"}
+
+// ListTimezones returns the sorted names of the locations of the time zone
+// database of the system, or of the Go installation, such as
+// "Europe/Paris". It returns only UTC if neither is found.
+func ListTimezones() []string {
+	timezones.once.Do(func() {
+		dirs := zoneDirs
+		if dir := os.Getenv("ZONEINFO"); dir != "" {
+			dirs = append([]string{dir}, dirs...)
+		}
+		names := map[string]bool{"UTC": true}
+		for _, dir := range dirs {
+			if zoneNamesDir(dir, names) {
+				break
+			}
+		}
+		if len(names) == 1 {
+			zoneNamesZip(filepath.Join(runtime.GOROOT(), "lib", "time", "zoneinfo.zip"), names)
+		}
+		for name := range names {
+			timezones.names = append(timezones.names, name)
+		}
+		sort.Strings(timezones.names)
+	})
+	return append([]string(nil), timezones.names...)
+}
+
+// isZoneName reports whether name, a file of a time zone database, is the
+// name of a location, excluding legacy and alternative trees.
+func isZoneName(name string) bool {
+	if name == "" || name[0] < 'A' || name[0] > 'Z' || strings.HasPrefix(name, "SystemV/") {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("/_-+", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+func zoneNamesDir(dir string, names map[string]bool) bool {
+	found := false
+	filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		name, err := filepath.Rel(dir, p)
+		if err != nil || !isZoneName(filepath.ToSlash(name)) {
+			return nil
+		}
+		head := make([]byte, 4)
+		if f, err := os.Open(p); err == nil {
+			f.Read(head)
+			f.Close()
+		}
+		if bytes.Equal(head, []byte("TZif")) {
+			names[filepath.ToSlash(name)] = true
+			found = true
+		}
+		return nil
+	})
+	return found
+}
+
+func zoneNamesZip(file string, names map[string]bool) {
+	z, err := zip.OpenReader(file)
+	if err != nil {
+		return
+	}
+	defer z.Close()
+	for _, f := range z.File {
+		if isZoneName(f.Name) {
+			names[f.Name] = true
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package funcmaps
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxTraceValue is the length in runes beyond which WriteReport shortens
+// arguments and results.
+const maxTraceValue = 60
+
+// TraceEntry is a function call recorded by Trace.
+type TraceEntry struct {
+	FuncCall
+	Args   []interface{}
+	Result interface{} // the result other than the error, if any
+}
+
+// TraceCollector holds the calls recorded by the functions returned by
+// Trace. It is safe for concurrent use.
+type TraceCollector struct {
+	mu      sync.Mutex
+	entries []TraceEntry
+}
+
+// Trace returns a copy of fm whose functions record their calls, with
+// their arguments, results and errors, in the returned collector, to find
+// out why a template produced unexpected output:
+//
+//	fm, trace := funcmaps.Trace(fm)
+//	t := template.Must(template.New("").Funcs(template.FuncMap(fm)).Parse(src))
+//	t.Execute(io.Discard, data)
+//	trace.WriteReport(os.Stderr)
+//
+// The collector records the calls of every execution using the returned
+// functions, and is not bound to one. Concurrent executions, such as of a
+// Templates shared by HTTP handlers, would interleave their calls, and
+// Reset would race with them, so the returned FuncMap must not be shared
+// by concurrent executions. To trace one execution of a shared template,
+// call Trace for it and execute a clone of the template:
+//
+//	fm, trace := funcmaps.Trace(fm)
+//	c, _ := t.Clone()
+//	c.Funcs(template.FuncMap(fm)).Execute(w, data)
+func Trace(fm FuncMap) (FuncMap, *TraceCollector) {
+	c := &TraceCollector{}
+	traced := wrapFuncs(fm, observeCall(func(call FuncCall, args, out []reflect.Value) {
+		e := TraceEntry{FuncCall: call, Args: callArgs(reflect.TypeOf(fm[call.Name]), args)}
+		if call.Err == nil && len(out) > 0 && out[0].Type() != errorType {
+			e.Result = valueInterface(out[0])
+		}
+		c.mu.Lock()
+		c.entries = append(c.entries, e)
+		c.mu.Unlock()
+	}))
+	return traced, c
+}
+
+// Calls returns the calls recorded so far, in order.
+func (c *TraceCollector) Calls() []TraceEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]TraceEntry(nil), c.entries...)
+}
+
+// Reset forgets the calls recorded so far.
+func (c *TraceCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = nil
+}
+
+// WriteReport writes the recorded calls to w, one per line, followed by a
+// summary:
+//
+//	1  toUpper("hi") => "HI" (2µs)
+//	2  mustToInt("x") failed: strconv.ParseInt: parsing "x": invalid syntax (1µs)
+//	2 calls, 1 failed, 3µs
+func (c *TraceCollector) WriteReport(w io.Writer) error {
+	calls := c.Calls()
+	var b strings.Builder
+	var total time.Duration
+	failed := 0
+	width := len(fmt.Sprint(len(calls)))
+	for i, e := range calls {
+		args := make([]string, len(e.Args))
+		for j, a := range e.Args {
+			args[j] = traceValue(a)
+		}
+		fmt.Fprintf(&b, "%-*d  %s(%s)", width, i+1, e.Name, strings.Join(args, ", "))
+		if e.Err != nil {
+			failed++
+			fmt.Fprintf(&b, " failed: %v", e.Err)
+		} else if e.Result != nil {
+			fmt.Fprintf(&b, " => %s", traceValue(e.Result))
+		}
+		fmt.Fprintf(&b, " (%v)\n", e.Duration)
+		total += e.Duration
+	}
+	fmt.Fprintf(&b, "%d calls, %d failed, %v\n", len(calls), failed, total)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// traceValue formats v as formatArg does, shortened to maxTraceValue runes.
+func traceValue(v interface{}) string {
+	s := formatArg(v)
+	if utf8.RuneCountInString(s) > maxTraceValue {
+		s = string([]rune(s)[:maxTraceValue-1]) + "…"
+	}
+	return s
+}
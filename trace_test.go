@@ -0,0 +1,37 @@
+package funcmaps
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	fm, trace := Trace(Combined(Default(), Convert()))
+	if _, err := execute(fm, `{{toUpper "hi"}}{{mustToInt "x"}}`, nil); err == nil {
+		t.Fatal("mustToInt of x: got nil error")
+	}
+	calls := trace.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("got %d calls, want 2", len(calls))
+	}
+	if c := calls[0]; c.Name != "toUpper" || c.Result != "HI" || c.Err != nil || len(c.Args) != 1 || c.Args[0] != "hi" {
+		t.Errorf("first call: got %+v", c)
+	}
+	if c := calls[1]; c.Name != "mustToInt" || c.Err == nil || c.Result != nil {
+		t.Errorf("second call: got %+v", c)
+	}
+	var b strings.Builder
+	if err := trace.WriteReport(&b); err != nil {
+		t.Fatal(err)
+	}
+	report := b.String()
+	for _, want := range []string{`1  toUpper("hi") => "HI"`, `2  mustToInt("x") failed: `, "2 calls, 1 failed"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report %q does not contain %q", report, want)
+		}
+	}
+	trace.Reset()
+	if n := len(trace.Calls()); n != 0 {
+		t.Errorf("Reset kept %d calls", n)
+	}
+}
@@ -22,13 +22,13 @@ import (
 // come from a trusted source, as it will be included verbatim in the template
 // output
 func Trusted() FuncMap {
-	return FuncMap{
+	return recordRisks(FuncMap{
 		"unsafeCSS":      CSS,
 		"unsafeHTML":     HTML,
 		"unsafeHTMLAttr": HTMLAttr,
 		"unsafeJS":       JS,
 		"unsafeURL":      URL,
-	}
+	})
 }
 
 func Debug() FuncMap {
@@ -38,9 +38,11 @@ func Debug() FuncMap {
 	}
 }
 
-// All (Default, Trusted, Debug)
-func All() FuncMap {
-	return Combined(Default(), Trusted(), Debug())
+// All (Default, Trusted, Debug), with the options applied to the combined
+// map, so that Without can also remove Trusted and Debug functions.
+func All(opts ...MapOption) FuncMap {
+	o := newMapOptions(opts)
+	return o.edit(Combined(defaultFuncs(o), Trusted(), Debug()))
 }
 
 // CSS returns a given string as html/template CSS content
@@ -0,0 +1,76 @@
+package funcmaps
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Types returns functions inspecting the types of values, so that templates
+// rendering values of different types, such as the blocks of a page, can
+// dispatch on them:
+//
+//	kindOf .          => "struct"
+//	typeOf .          => "*blocks.Image"
+//	typeIs "Image" .  => true
+//	isNil .Parent     => true
+//	isSlice .Items    => true
+func Types() FuncMap {
+	return FuncMap{
+		"kindOf":   KindOf,
+		"typeOf":   TypeOf,
+		"typeIs":   TypeIs,
+		"isNil":    IsNil,
+		"isSlice":  func(v interface{}) bool { return kindIs(v, reflect.Slice) },
+		"isMap":    func(v interface{}) bool { return kindIs(v, reflect.Map) },
+		"isStruct": func(v interface{}) bool { return kindIs(v, reflect.Struct) },
+	}
+}
+
+// KindOf returns the kind of v, such as "string", "slice" or "ptr", or
+// "invalid" for nil.
+func KindOf(v interface{}) string {
+	return reflect.ValueOf(v).Kind().String()
+}
+
+// TypeOf returns the type of v as written in Go, such as "[]string" or
+// "*main.User", or "<nil>" for nil.
+func TypeOf(v interface{}) string {
+	return fmt.Sprintf("%T", v)
+}
+
+// TypeIs reports whether the type of v, or the type v points to, is name,
+// which is either the type as written by TypeOf or the name of a named
+// type without its package, such as "User" for a *main.User.
+func TypeIs(name string, v interface{}) bool {
+	typ := reflect.TypeOf(v)
+	if typ == nil {
+		return name == "<nil>"
+	}
+	if typ.String() == name {
+		return true
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.String() == name || typ.Name() != "" && typ.Name() == name
+}
+
+// IsNil reports whether v is nil, or a nil pointer, map, slice, function,
+// channel or interface.
+func IsNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// kindIs reports whether v, or the value v points to, is of kind k.
+func kindIs(v interface{}, k reflect.Kind) bool {
+	rv, isNil := indirect(reflect.ValueOf(v))
+	return !isNil && rv.Kind() == k
+}
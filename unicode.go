@@ -0,0 +1,131 @@
+package funcmaps
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Unicode returns functions normalizing user supplied text.
+func Unicode() FuncMap {
+	return FuncMap{
+		"emojify":          Emojify,
+		"deEmoji":          DeEmoji,
+		"normalizeNFC":     norm.NFC.String,
+		"normalizeNFD":     norm.NFD.String,
+		"removeDiacritics": RemoveDiacritics,
+		"runeLen":          utf8.RuneCountInString,
+		"graphemeLen":      GraphemeLen,
+		"graphemeTrunc":    GraphemeTrunc,
+		"graphemeRev":      GraphemeReverse,
+		"firstGrapheme":    FirstGrapheme,
+	}
+}
+
+var emojiShortcode = regexp.MustCompile(`:[a-z0-9_+\-]+:`)
+
+// Emojis maps shortcodes, as used by Emojify, to emoji.
+// Applications may add their own.
+var Emojis = map[string]string{
+	":+1:":               "👍",
+	":-1:":               "👎",
+	":100:":              "💯",
+	":angry:":            "😠",
+	":bug:":              "🐛",
+	":check:":            "✔️",
+	":clap:":             "👏",
+	":coffee:":           "☕",
+	":confused:":         "😕",
+	":cry:":              "😢",
+	":eyes:":             "👀",
+	":fire:":             "🔥",
+	":grin:":             "😁",
+	":heart:":            "❤️",
+	":heavy_check_mark:": "✔️",
+	":hourglass:":        "⌛",
+	":joy:":              "😂",
+	":laughing:":         "😆",
+	":lock:":             "🔒",
+	":memo:":             "📝",
+	":ok_hand:":          "👌",
+	":party_popper:":     "🎉",
+	":pray:":             "🙏",
+	":rocket:":           "🚀",
+	":rotating_light:":   "🚨",
+	":sad:":              "😞",
+	":see_no_evil:":      "🙈",
+	":smile:":            "😄",
+	":smiley:":           "😃",
+	":sparkles:":         "✨",
+	":star:":             "⭐",
+	":tada:":             "🎉",
+	":thinking:":         "🤔",
+	":thumbsdown:":       "👎",
+	":thumbsup:":         "👍",
+	":warning:":          "⚠️",
+	":wave:":             "👋",
+	":white_check_mark:": "✅",
+	":wink:":             "😉",
+	":wrench:":           "🔧",
+	":x:":                "❌",
+	":zap:":              "⚡",
+}
+
+// Emojify replaces known emoji shortcodes, such as ":tada:", with emoji.
+// Unknown shortcodes are left as they are.
+func Emojify(s string) string {
+	return emojiShortcode.ReplaceAllStringFunc(s, func(code string) string {
+		if e, ok := Emojis[code]; ok {
+			return e
+		}
+		return code
+	})
+}
+
+// DeEmoji removes emoji, including modifiers, flags and joined sequences.
+func DeEmoji(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isEmojiRune(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func isEmojiRune(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // pictographs, emoticons, flags, modifiers
+	case r >= 0x2600 && r <= 0x27BF: // miscellaneous symbols and dingbats
+	case r >= 0x2B00 && r <= 0x2BFF: // arrows and stars
+	case r >= 0x231A && r <= 0x23FF: // watch, hourglass and media controls
+	case r >= 0xE0020 && r <= 0xE007F: // tag sequences
+	case r >= 0xFE00 && r <= 0xFE0F: // variation selectors
+	case r == 0x200D, r == 0x20E3: // zero width joiner, keycap
+	default:
+		return false
+	}
+	return true
+}
+
+// diacriticFree maps letters that do not decompose into a base letter and a
+// combining mark to their closest ASCII spelling.
+var diacriticFree = strings.NewReplacer(
+	"ø", "o", "Ø", "O", "ł", "l", "Ł", "L", "đ", "d", "Đ", "D",
+	"ß", "ss", "æ", "ae", "Æ", "AE", "œ", "oe", "Œ", "OE",
+)
+
+// RemoveDiacritics strips accents and other marks from letters, so "Crème
+// Brûlée" gives "Creme Brulee".
+func RemoveDiacritics(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	rs, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return diacriticFree.Replace(rs)
+}
@@ -0,0 +1,146 @@
+package funcmaps
+
+import (
+	"fmt"
+	"html/template"
+	"net/mail"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// URLs returns functions building links and joining URL paths:
+//
+//	pathJoin "docs" "../api" "x.html"             => "api/x.html"
+//	absURL "https://a.com/docs/" "img/x.png"       => "https://a.com/docs/img/x.png"
+//	relURL "https://a.com/docs/a/" "/docs/b/x.png" => "../b/x.png"
+//	canonicalURL "HTTPS://A.com:443/a/../b?z=1&a=2#top" => "https://a.com/b?a=2&z=1"
+func URLs() FuncMap {
+	return FuncMap{
+		"telLink":      TelLink,
+		"mailtoLink":   MailtoLink,
+		"pathJoin":     path.Join,
+		"pathBase":     path.Base,
+		"pathDir":      path.Dir,
+		"pathExt":      path.Ext,
+		"absURL":       AbsURL,
+		"relURL":       RelURL,
+		"canonicalURL": CanonicalURL,
+	}
+}
+
+// AbsURL resolves target, such as "../img/a.png", relative to the URL
+// base, as a browser would for a link in the page at base.
+func AbsURL(base, target string) (string, error) {
+	b, t, err := parseURLs(base, target)
+	if err != nil {
+		return "", err
+	}
+	return b.ResolveReference(t).String(), nil
+}
+
+// RelURL returns the shortest reference to target from the page at base,
+// such as "../b/x.png". target is resolved against base first, and is
+// returned absolute if it has another scheme or host.
+func RelURL(base, target string) (string, error) {
+	b, t, err := parseURLs(base, target)
+	if err != nil {
+		return "", err
+	}
+	t = b.ResolveReference(t)
+	if t.Scheme != b.Scheme || t.Host != b.Host || t.Opaque != "" {
+		return t.String(), nil
+	}
+	from := strings.Split(b.EscapedPath(), "/")
+	to := strings.Split(t.EscapedPath(), "/")
+	from = from[:len(from)-1] // the directory of the page
+	i := 0
+	for i < len(from) && i < len(to)-1 && from[i] == to[i] {
+		i++
+	}
+	rel := strings.Repeat("../", len(from)-i) + strings.Join(to[i:], "/")
+	if rel == "" {
+		rel = "./"
+	} else if first := strings.SplitN(rel, "/", 2)[0]; strings.Contains(first, ":") {
+		rel = "./" + rel // not a scheme
+	}
+	if t.RawQuery != "" || t.ForceQuery {
+		rel += "?" + t.RawQuery
+	}
+	if t.Fragment != "" {
+		rel += "#" + t.EscapedFragment()
+	}
+	return rel, nil
+}
+
+// CanonicalURL returns the canonical form of u, for rel="canonical" links
+// and comparisons: a lowercase scheme and host, without the default port,
+// a path without dot segments, query parameters sorted by key, and no
+// fragment.
+func CanonicalURL(u string) (string, error) {
+	p, err := url.Parse(u)
+	if err != nil {
+		return "", err
+	}
+	p.Scheme = strings.ToLower(p.Scheme)
+	p.Host = strings.ToLower(p.Host)
+	if port := p.Port(); port == "80" && p.Scheme == "http" || port == "443" && p.Scheme == "https" {
+		p.Host = strings.TrimSuffix(p.Host, ":"+port)
+	}
+	if p.Path != "" {
+		clean := path.Clean(p.Path)
+		if strings.HasSuffix(p.Path, "/") && clean != "/" {
+			clean += "/"
+		}
+		p.Path, p.RawPath = clean, ""
+	} else if p.Host != "" {
+		p.Path = "/"
+	}
+	p.RawQuery = p.Query().Encode()
+	p.Fragment, p.RawFragment, p.ForceQuery = "", "", false
+	return p.String(), nil
+}
+
+func parseURLs(base, target string) (*url.URL, *url.URL, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return nil, nil, err
+	}
+	t, err := url.Parse(target)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, t, nil
+}
+
+// TelLink returns a tel: URL for a phone number, keeping only digits, a
+// leading plus sign and the pause characters "," and ";".
+func TelLink(number string) (template.URL, error) {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(number) {
+		switch {
+		case r >= '0' && r <= '9', r == ',', r == ';':
+			b.WriteRune(r)
+		case r == '+' && b.Len() == 0:
+			b.WriteRune(r)
+		}
+	}
+	if strings.Trim(b.String(), "+,;") == "" {
+		return "", fmt.Errorf("telLink: no digits in %q", number)
+	}
+	return template.URL("tel:" + b.String()), nil
+}
+
+// MailtoLink returns a mailto: URL for addr, with an optional subject.
+// addr may include a display name, as in "Ada <ada@example.com>".
+func MailtoLink(addr, subject string) (template.URL, error) {
+	a, err := mail.ParseAddress(addr)
+	if err != nil {
+		return "", fmt.Errorf("mailtoLink: %v", err)
+	}
+	u := "mailto:" + url.PathEscape(a.Address)
+	if subject != "" {
+		u += "?subject=" + strings.Replace(url.QueryEscape(subject), "+", "%20", -1)
+	}
+	return template.URL(u), nil
+}
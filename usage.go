@@ -0,0 +1,199 @@
+package funcmaps
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"text/template/parse"
+)
+
+// builtins lists the functions predefined by text/template and
+// html/template.
+var builtins = map[string]bool{
+	"and": true, "call": true, "html": true, "index": true, "slice": true,
+	"js": true, "len": true, "not": true, "or": true, "print": true,
+	"printf": true, "println": true, "urlquery": true,
+	"eq": true, "ge": true, "gt": true, "le": true, "lt": true, "ne": true,
+}
+
+// FuncUsage maps the names of the functions called by a set of templates to
+// the sorted names of the templates calling them. Predefined functions such
+// as printf and eq are not included.
+type FuncUsage map[string][]string
+
+// ParseUsage parses the templates in fsys matching patterns, as
+// template.ParseFS does but without any functions, and reports the
+// functions they call. Unlike parsing for execution, calls of undefined
+// functions are not errors, so that they can be reported with Missing.
+func ParseUsage(fsys fs.FS, patterns ...string) (FuncUsage, error) {
+	names, err := globFiles(fsys, patterns)
+	if err != nil {
+		return nil, err
+	}
+	trees := map[string]*parse.Tree{}
+	for _, name := range names {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := parseUnchecked(path.Base(name), string(b), trees); err != nil {
+			return nil, err
+		}
+	}
+	u := FuncUsage{}
+	for name, tree := range trees {
+		u.add(name, tree.Root)
+	}
+	return u.sorted(), nil
+}
+
+// parseUnchecked parses text as the template name into trees, allowing
+// calls of undefined functions.
+func parseUnchecked(name, text string, trees map[string]*parse.Tree) error {
+	t := parse.New(name)
+	t.Mode = parse.SkipFuncCheck
+	_, err := t.Parse(text, "", "", trees)
+	return err
+}
+
+// Usage reports the functions called by the HTML and text templates parsed
+// so far, such as to prune the FuncMap of a set of trusted templates with
+// Prune.
+func (t *Templates) Usage() (FuncUsage, error) {
+	set, err := t.current()
+	if err != nil {
+		return nil, err
+	}
+	u := FuncUsage{}
+	for _, c := range set.master.Templates() {
+		if c.Tree != nil {
+			u.add(c.Name(), c.Tree.Root)
+		}
+	}
+	for _, c := range set.text.Templates() {
+		if c.Tree != nil {
+			u.add(c.Name(), c.Tree.Root)
+		}
+	}
+	return u.sorted(), nil
+}
+
+// add records the functions called within node by the template name.
+func (u FuncUsage) add(name string, node parse.Node) {
+	walkFuncs(node, func(id *parse.IdentifierNode, nargs int) {
+		if !builtins[id.Ident] {
+			u[id.Ident] = append(u[id.Ident], name)
+		}
+	})
+}
+
+// walkFuncs calls fn for every function call within node, with the number
+// of arguments given, including the result of the previous command of a
+// pipeline.
+func walkFuncs(node parse.Node, fn func(id *parse.IdentifierNode, nargs int)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			walkFuncs(c, fn)
+		}
+	case *parse.ActionNode:
+		walkFuncs(n.Pipe, fn)
+	case *parse.IfNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.RangeNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.WithNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.TemplateNode:
+		walkFuncs(n.Pipe, fn)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for i, c := range n.Cmds {
+			walkCommand(c, i > 0, fn)
+		}
+	case *parse.CommandNode:
+		walkCommand(n, false, fn)
+	case *parse.ChainNode:
+		walkFuncs(n.Node, fn)
+	case *parse.IdentifierNode:
+		fn(n, 0)
+	}
+}
+
+func walkBranch(n *parse.BranchNode, fn func(id *parse.IdentifierNode, nargs int)) {
+	walkFuncs(n.Pipe, fn)
+	walkFuncs(n.List, fn)
+	walkFuncs(n.ElseList, fn)
+}
+
+// walkCommand calls fn for the function called by c, if any, and for the
+// functions called by its arguments.
+func walkCommand(c *parse.CommandNode, piped bool, fn func(id *parse.IdentifierNode, nargs int)) {
+	for i, arg := range c.Args {
+		if id, ok := arg.(*parse.IdentifierNode); ok && i == 0 {
+			nargs := len(c.Args) - 1
+			if piped {
+				nargs++
+			}
+			fn(id, nargs)
+			continue
+		}
+		walkFuncs(arg, fn)
+	}
+}
+
+// sorted sorts the template names of each function, removing duplicates.
+func (u FuncUsage) sorted() FuncUsage {
+	for fn, names := range u {
+		sort.Strings(names)
+		j := 0
+		for i, name := range names {
+			if i == 0 || name != names[j-1] {
+				names[j] = name
+				j++
+			}
+		}
+		u[fn] = names[:j]
+	}
+	return u
+}
+
+// Names returns the sorted names of the functions called.
+func (u FuncUsage) Names() []string {
+	names := make([]string, 0, len(u))
+	for name := range u {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Missing returns the sorted names of the functions called but not in fm,
+// which would fail parsing or execution with fm.
+func (u FuncUsage) Missing(fm FuncMap) []string {
+	var names []string
+	for _, name := range u.Names() {
+		if _, ok := fm[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Prune returns the functions of fm called by the templates, leaving out
+// the unused ones, such as to reduce what a sandboxed set of templates can
+// call to what it was reviewed with.
+func (u FuncUsage) Prune(fm FuncMap) FuncMap {
+	pruned := FuncMap{}
+	for name := range u {
+		if f, ok := fm[name]; ok {
+			pruned[name] = f
+		}
+	}
+	return pruned
+}
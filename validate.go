@@ -0,0 +1,94 @@
+package funcmaps
+
+import (
+	"encoding/json"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Validate returns predicates reporting whether a value is valid text of a
+// kind, such as for re-rendering a form with its errors:
+//
+//	{{if not (isEmail .Form.Email)}}<p class="error">Invalid email</p>{{end}}
+//
+// Values are converted to text as by toLower, and values that cannot be
+// are not valid.
+func Validate() FuncMap {
+	return FuncMap{
+		"isEmail":    validator(IsEmail),
+		"isURL":      validator(IsURL),
+		"isUUID":     validator(IsUUID),
+		"isHostname": validator(IsHostname),
+		"isJSON":     validator(IsJSON),
+		"isNumeric":  validator(IsNumeric),
+	}
+}
+
+// validator adapts a string predicate to accept any value toText accepts.
+func validator(f func(string) bool) func(interface{}) bool {
+	return func(v interface{}) bool {
+		s, err := toText(v)
+		return err == nil && f(s)
+	}
+}
+
+var (
+	uuidRe    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	labelRe   = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+	numericRe = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+)
+
+// IsEmail reports whether s is a bare email address, such as
+// "ada@example.com", without a display name or angle brackets, whose domain
+// is a hostname.
+func IsEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s || addr.Name != "" {
+		return false
+	}
+	at := strings.LastIndexByte(s, '@')
+	return IsHostname(s[at+1:])
+}
+
+// IsURL reports whether s is an absolute URL with a host, such as
+// "https://example.com/a".
+func IsURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != "" && !strings.ContainsAny(s, " \t\r\n")
+}
+
+// IsUUID reports whether s is a UUID in its canonical form of 36
+// hexadecimal digits and hyphens, of any version.
+func IsUUID(s string) bool {
+	return uuidRe.MatchString(s)
+}
+
+// IsHostname reports whether s is a valid hostname as of RFC 1123: dot
+// separated labels of letters, digits and hyphens, not starting or ending
+// with a hyphen, of at most 63 characters and 253 in total. A final dot is
+// allowed.
+func IsHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if !labelRe.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsJSON reports whether s is valid JSON.
+func IsJSON(s string) bool {
+	return json.Valid([]byte(s))
+}
+
+// IsNumeric reports whether s is a decimal number, such as "42", "-1.5"
+// or "6.02e23", without surrounding spaces.
+func IsNumeric(s string) bool {
+	return numericRe.MatchString(s)
+}
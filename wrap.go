@@ -0,0 +1,118 @@
+package funcmaps
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// interceptor is called in place of a wrapped function with the function's
+// name and arguments, and calls next to call the function itself.
+type interceptor func(name string, args []reflect.Value, next func([]reflect.Value) []reflect.Value) []reflect.Value
+
+// wrapFuncs returns a copy of fm with every function wrapped by intercept.
+// Values that are not functions are copied as they are.
+func wrapFuncs(fm FuncMap, intercept interceptor) FuncMap {
+	rs := make(FuncMap, len(fm))
+	for name, fn := range fm {
+		rs[name] = wrapFunc(name, fn, intercept)
+	}
+	return rs
+}
+
+// wrapFunc returns a function of the same type as fn calling intercept.
+func wrapFunc(name string, fn interface{}, intercept interceptor) interface{} {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func || fv.IsNil() {
+		return fn
+	}
+	typ := fv.Type()
+	next := fv.Call
+	if typ.IsVariadic() {
+		next = fv.CallSlice
+	}
+	return reflect.MakeFunc(typ, func(args []reflect.Value) []reflect.Value {
+		return intercept(name, args, next)
+	}).Interface()
+}
+
+// callArgs returns the arguments of a call as passed by the caller,
+// expanding the variadic arguments of variadic functions.
+func callArgs(typ reflect.Type, args []reflect.Value) []interface{} {
+	rs := make([]interface{}, 0, len(args))
+	for i, a := range args {
+		if typ.IsVariadic() && i == len(args)-1 {
+			for j := 0; j < a.Len(); j++ {
+				rs = append(rs, valueInterface(a.Index(j)))
+			}
+			continue
+		}
+		rs = append(rs, valueInterface(a))
+	}
+	return rs
+}
+
+func valueInterface(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// callError returns the error result of a call, if any.
+func callError(out []reflect.Value) error {
+	if len(out) == 2 && out[1].Type() == errorType && !out[1].IsNil() {
+		return out[1].Interface().(error)
+	}
+	return nil
+}
+
+// failCall returns the results of a call of a function of type typ failing
+// with err: zero values and err when the function returns an error. Other
+// functions cannot fail, so failCall panics with err, which the template
+// packages report as the error of the call.
+func failCall(typ reflect.Type, err error) []reflect.Value {
+	if typ.NumOut() == 0 || typ.Out(typ.NumOut()-1) != errorType {
+		panic(err)
+	}
+	out := make([]reflect.Value, typ.NumOut())
+	for i := range out {
+		out[i] = reflect.Zero(typ.Out(i))
+	}
+	out[len(out)-1] = reflect.ValueOf(&err).Elem()
+	return out
+}
+
+// observeCall returns an interceptor calling done after every call with
+// the call described as a FuncCall, its arguments and its results. The
+// error of the FuncCall is the error result of the call, or its panic, in
+// which case there are no results and the panic is raised again once done
+// returns.
+func observeCall(done func(c FuncCall, args, out []reflect.Value)) interceptor {
+	return func(name string, args []reflect.Value, next func([]reflect.Value) []reflect.Value) (out []reflect.Value) {
+		start := time.Now()
+		defer func() {
+			c := FuncCall{Name: name, Duration: time.Since(start)}
+			p := recover()
+			if p != nil {
+				c.Err = panicError(p)
+				out = nil
+			} else {
+				c.Err = callError(out)
+			}
+			done(c, args, out)
+			if p != nil {
+				panic(p)
+			}
+		}()
+		return next(args)
+	}
+}
+
+// panicError returns the value of a panic as an error.
+func panicError(p interface{}) error {
+	if err, ok := p.(error); ok {
+		return err
+	}
+	return fmt.Errorf("%v", p)
+}